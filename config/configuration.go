@@ -8,28 +8,46 @@ viper.
 package config
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/spf13/viper"
 )
 
 const (
-	authURI     = "auth.uri"
-	serverHost  = "server.host"
-	serverPort  = "server.port"
-	metricsPort = "server.port"
-	sentryDSN   = "sentry.dsn"
+	authURI              = "auth.uri"
+	serverHost           = "server.host"
+	serverPort           = "server.port"
+	metricsPort          = "server.port"
+	sentryDSN            = "sentry.dsn"
+	precedence           = "detector.precedence"
+	rulesFile            = "detector.rules"
+	gitlabHosts          = "gitlab.hosts"
+	bitbucketAPIURL      = "bitbucket.api.url"
+	bitbucketServerHosts = "bitbucket.server.hosts"
+	githubEnterprise     = "github.enterprise"
+	uploadMaxSize        = "upload.max.size"
+	uploadMaxEntries     = "upload.max.entries"
+	uploadMaxExtracted   = "upload.max.extracted"
 )
 
 const (
-	defaultAuth = "https://auth.prod-preview.openshift.io"
-	defaultHost = "localhost"
-	defaultPort = "8099"
+	defaultAuth               = "https://auth.prod-preview.openshift.io"
+	defaultHost               = "localhost"
+	defaultPort               = "8099"
+	defaultGitLabHosts        = "gitlab.com"
+	defaultBitbucketAPIURL    = "https://api.bitbucket.org"
+	defaultUploadMaxSize      = 10 << 20
+	defaultUploadMaxEntries   = 10000
+	defaultUploadMaxExtracted = 100 << 20
 )
 
 const (
-	prefix       = "BUILD_TOOL_DETECTOR"
-	authKeysPath = "/api/token/keys"
+	prefix          = "BUILD_TOOL_DETECTOR"
+	authKeysPath    = "/api/token/keys"
+	comma           = ","
+	equals          = "="
+	enterpriseAPIV3 = "https://%s/api/v3/"
 )
 
 // Configuration for build tool detector.
@@ -80,6 +98,97 @@ func (c *Configuration) GetSentryDSN() string {
 	return c.viper.GetString(sentryDSN)
 }
 
+// GetDetectorPrecedence returns the build tool types,
+// highest precedence first, used to pick the result
+// when several build tools are detected. It is set
+// as a comma separated list, e.g. "maven,nodejs".
+func (c *Configuration) GetDetectorPrecedence() []string {
+	return c.getList(precedence)
+}
+
+// GetDetectorRulesFile returns the path of the yaml
+// file declaring detection rules, empty if the
+// built-in detectors are used as is.
+func (c *Configuration) GetDetectorRulesFile() string {
+	return c.viper.GetString(rulesFile)
+}
+
+// GetGitLabHosts returns the hosts of the gitlab
+// instances, gitlab.com and self-hosted, set
+// as a comma separated list.
+func (c *Configuration) GetGitLabHosts() []string {
+	return c.getList(gitlabHosts)
+}
+
+// IsGitLabHost returns whether
+// the host is a gitlab instance.
+func (c *Configuration) IsGitLabHost(host string) bool {
+	return c.hasHost(gitlabHosts, host)
+}
+
+// GetBitbucketAPIURL returns the url
+// of the bitbucket cloud api.
+func (c *Configuration) GetBitbucketAPIURL() string {
+	return c.viper.GetString(bitbucketAPIURL)
+}
+
+// GetBitbucketServerHosts returns the hosts of the
+// self-hosted bitbucket server instances, set
+// as a comma separated list.
+func (c *Configuration) GetBitbucketServerHosts() []string {
+	return c.getList(bitbucketServerHosts)
+}
+
+// IsBitbucketServerHost returns whether the
+// host is a bitbucket server instance.
+func (c *Configuration) IsBitbucketServerHost(host string) bool {
+	return c.hasHost(bitbucketServerHosts, host)
+}
+
+// GetGitHubEnterpriseURLs returns the api base urls of
+// the github enterprise instances keyed by host, set as
+// a comma separated list of host=url pairs, e.g.
+// "ghe.example.com=https://ghe.example.com/api/v3/".
+// Hosts without an url default to https://host/api/v3/.
+func (c *Configuration) GetGitHubEnterpriseURLs() map[string]string {
+	urls := make(map[string]string)
+	for _, value := range c.getList(githubEnterprise) {
+		pair := strings.SplitN(value, equals, 2)
+		host := strings.ToLower(strings.TrimSpace(pair[0]))
+		if len(pair) == 1 || strings.TrimSpace(pair[1]) == "" {
+			urls[host] = fmt.Sprintf(enterpriseAPIV3, host)
+			continue
+		}
+		urls[host] = strings.TrimSpace(pair[1])
+	}
+	return urls
+}
+
+// GetGitHubEnterpriseURL returns the api base
+// url of the github enterprise host.
+func (c *Configuration) GetGitHubEnterpriseURL(host string) (string, bool) {
+	apiURL, ok := c.GetGitHubEnterpriseURLs()[strings.ToLower(host)]
+	return apiURL, ok
+}
+
+// GetUploadMaxSize returns the maximum size,
+// in bytes, of the archives uploaded.
+func (c *Configuration) GetUploadMaxSize() int64 {
+	return c.viper.GetInt64(uploadMaxSize)
+}
+
+// GetUploadMaxEntries returns the maximum number of
+// files and directories of the archives uploaded.
+func (c *Configuration) GetUploadMaxEntries() int {
+	return c.viper.GetInt(uploadMaxEntries)
+}
+
+// GetUploadMaxExtracted returns the maximum size, in
+// bytes, of the files extracted from an archive.
+func (c *Configuration) GetUploadMaxExtracted() int64 {
+	return c.viper.GetInt64(uploadMaxExtracted)
+}
+
 // GetAuthKeysPath provides a URL path to be called for retrieving the keys.
 func (c *Configuration) GetAuthKeysPath() string {
 	// Fixed with https://github.com/fabric8-services/fabric8-common/pull/25.
@@ -92,10 +201,38 @@ func (c *Configuration) GetDevModePrivateKey() []byte {
 	return nil
 }
 
+// getList returns the values of a comma
+// separated list, ignoring empty values.
+func (c *Configuration) getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.viper.GetString(key), comma) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// hasHost returns whether the host is
+// part of the comma separated list.
+func (c *Configuration) hasHost(key string, host string) bool {
+	for _, value := range c.getList(key) {
+		if strings.EqualFold(value, host) {
+			return true
+		}
+	}
+	return false
+}
+
 // setConfigDefaults sets defaults for configuration.
 func (c *Configuration) setConfigDefaults() {
 	c.viper.SetDefault(authURI, defaultAuth)
 	c.viper.SetDefault(serverHost, defaultHost)
 	c.viper.SetDefault(serverPort, defaultPort)
 	c.viper.SetDefault(metricsPort, defaultPort)
+	c.viper.SetDefault(gitlabHosts, defaultGitLabHosts)
+	c.viper.SetDefault(bitbucketAPIURL, defaultBitbucketAPIURL)
+	c.viper.SetDefault(uploadMaxSize, defaultUploadMaxSize)
+	c.viper.SetDefault(uploadMaxEntries, defaultUploadMaxEntries)
+	c.viper.SetDefault(uploadMaxExtracted, defaultUploadMaxExtracted)
 }
@@ -27,6 +27,16 @@ var _ = Describe("Configuration", func() {
 			Expect(configuration.GetAuthServiceURL()).Should(Equal("https://auth.prod-preview.openshift.io"), "the auth url should default to https://auth.prod-preview.openshift.io")
 			Expect(configuration.GetSentryDSN()).Should(Equal(""), "the sentry dsn should default to empty")
 			Expect(configuration.GetAuthKeysPath()).Should(Equal("/api/token/keys"), "the sentry dsn should return /api/token/keys")
+			Expect(configuration.GetDetectorPrecedence()).Should(BeEmpty(), "the detector precedence should default to empty")
+			Expect(configuration.GetDetectorRulesFile()).Should(BeEmpty(), "the detector rules file should default to empty")
+			Expect(configuration.GetGitLabHosts()).Should(Equal([]string{"gitlab.com"}), "the gitlab hosts should default to gitlab.com")
+			Expect(configuration.IsGitLabHost("gitlab.com")).Should(BeTrue(), "gitlab.com should be a gitlab host")
+			Expect(configuration.GetBitbucketAPIURL()).Should(Equal("https://api.bitbucket.org"), "the bitbucket api url should default to https://api.bitbucket.org")
+			Expect(configuration.GetBitbucketServerHosts()).Should(BeEmpty(), "the bitbucket server hosts should default to empty")
+			Expect(configuration.GetGitHubEnterpriseURLs()).Should(BeEmpty(), "the github enterprise urls should default to empty")
+			Expect(configuration.GetUploadMaxSize()).Should(Equal(int64(10<<20)), "the upload max size should default to 10 MiB")
+			Expect(configuration.GetUploadMaxEntries()).Should(Equal(10000), "the upload max entries should default to 10000")
+			Expect(configuration.GetUploadMaxExtracted()).Should(Equal(int64(100<<20)), "the upload max extracted size should default to 100 MiB")
 		})
 	})
 
@@ -39,6 +49,14 @@ var _ = Describe("Configuration", func() {
 			os.Setenv("BUILD_TOOL_DETECTOR_SERVER_HOST", "test")
 			os.Setenv("BUILD_TOOL_DETECTOR_AUTH_URI", "test")
 			os.Setenv("BUILD_TOOL_DETECTOR_SENTRY_DSN", "test")
+			os.Setenv("BUILD_TOOL_DETECTOR_DETECTOR_PRECEDENCE", "nodejs, maven")
+			os.Setenv("BUILD_TOOL_DETECTOR_DETECTOR_RULES", "/etc/build-tool-detector/rules.yaml")
+			os.Setenv("BUILD_TOOL_DETECTOR_GITLAB_HOSTS", "gitlab.com,gitlab.example.com")
+			os.Setenv("BUILD_TOOL_DETECTOR_BITBUCKET_API_URL", "test")
+			os.Setenv("BUILD_TOOL_DETECTOR_BITBUCKET_SERVER_HOSTS", "bitbucket.example.com")
+			os.Setenv("BUILD_TOOL_DETECTOR_GITHUB_ENTERPRISE", "ghe.example.com, GitHub.Internal=http://localhost:8080/api/v3/")
+			os.Setenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_SIZE", "1024")
+			os.Setenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_ENTRIES", "10")
 			configuration = config.New()
 		})
 		AfterEach(func() {
@@ -48,6 +66,14 @@ var _ = Describe("Configuration", func() {
 			os.Unsetenv("BUILD_TOOL_DETECTOR_SERVER_HOST")
 			os.Unsetenv("BUILD_TOOL_DETECTOR_AUTH_URI")
 			os.Unsetenv("BUILD_TOOL_DETECTOR_SENTRY_DSN")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_DETECTOR_PRECEDENCE")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_DETECTOR_RULES")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_GITLAB_HOSTS")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_BITBUCKET_API_URL")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_BITBUCKET_SERVER_HOSTS")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_GITHUB_ENTERPRISE")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_SIZE")
+			os.Unsetenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_ENTRIES")
 		})
 		It("Configuration defaults - test defaults are overriden", func() {
 			Expect(configuration.GetHost()).Should(Equal("test"), "the host should override to test")
@@ -55,6 +81,20 @@ var _ = Describe("Configuration", func() {
 			Expect(configuration.GetMetricsPort()).Should(Equal("1234"), "the metrics port should override to 1234")
 			Expect(configuration.GetAuthServiceURL()).Should(Equal("test"), "the auth url should override to test")
 			Expect(configuration.GetSentryDSN()).Should(Equal("test"), "the sentry dsn should override to test")
+			Expect(configuration.GetDetectorPrecedence()).Should(Equal([]string{"nodejs", "maven"}), "the detector precedence should override to nodejs,maven")
+			Expect(configuration.GetDetectorRulesFile()).Should(Equal("/etc/build-tool-detector/rules.yaml"), "the detector rules file should override")
+			Expect(configuration.IsGitLabHost("gitlab.example.com")).Should(BeTrue(), "gitlab.example.com should be a gitlab host")
+			Expect(configuration.GetBitbucketAPIURL()).Should(Equal("test"), "the bitbucket api url should override to test")
+			Expect(configuration.IsBitbucketServerHost("bitbucket.example.com")).Should(BeTrue(), "bitbucket.example.com should be a bitbucket server host")
+			Expect(configuration.GetGitHubEnterpriseURLs()).Should(Equal(map[string]string{
+				"ghe.example.com": "https://ghe.example.com/api/v3/",
+				"github.internal": "http://localhost:8080/api/v3/",
+			}), "the github enterprise urls should override to ghe.example.com and github.internal")
+			Expect(configuration.GetUploadMaxSize()).Should(Equal(int64(1024)), "the upload max size should override to 1024")
+			Expect(configuration.GetUploadMaxEntries()).Should(Equal(10), "the upload max entries should override to 10")
+			apiURL, ok := configuration.GetGitHubEnterpriseURL("github.internal")
+			Expect(ok).Should(BeTrue(), "github.internal should be a github enterprise host")
+			Expect(apiURL).Should(Equal("http://localhost:8080/api/v3/"), "the api url of github.internal should be configured")
 		})
 	})
 })
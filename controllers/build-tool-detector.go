@@ -12,11 +12,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/fabric8-services/build-tool-detector/app"
 	"github.com/fabric8-services/build-tool-detector/config"
 	errs "github.com/fabric8-services/build-tool-detector/controllers/error"
+	"github.com/fabric8-services/build-tool-detector/domain/render"
 	"github.com/fabric8-services/build-tool-detector/domain/repository"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/archive"
 	"github.com/fabric8-services/build-tool-detector/domain/repository/github"
 	"github.com/fabric8-services/build-tool-detector/domain/types"
 	"github.com/fabric8-services/build-tool-detector/log"
@@ -36,6 +39,7 @@ const (
 	contentType                 = "Content-Type"
 	applicationJSON             = "application/json"
 	buildToolDetectorController = "BuildToolDetectorController"
+	detailedView                = "detailed"
 )
 
 // BuildToolDetectorController implements the build-tool-detector resource.
@@ -57,48 +61,147 @@ func (c *BuildToolDetectorController) Show(ctx *app.ShowBuildToolDetectorContext
 	rawURL := ctx.URL
 	ctx.ResponseWriter.Header().Set(contentType, applicationJSON)
 
-	repositoryService, err := repository.CreateService(&ctx.Context, rawURL, ctx.Branch, c.Configuration)
+	detectCtx := ctx.Context
+	var explanation *types.Explanation
+	if ctx.Explain != nil && *ctx.Explain {
+		detectCtx, explanation = types.WithExplanation(detectCtx)
+	}
+
+	repositoryService, err := repository.CreateService(&detectCtx, rawURL, ctx.Branch, ctx.Ref, ctx.Path, c.Configuration)
 	if err != nil {
-		return handleError(ctx, err)
+		return handleError(ctx, ctx.ResponseData, err)
+	}
+	// An unknown build tool is still reported
+	// along with the revision analyzed.
+	detection, err := repositoryService.DetectBuildTool(detectCtx)
+	if err != nil && err != types.ErrFailedContentRetrieval {
+		return handleError(ctx, ctx.ResponseData, err)
+	}
+
+	return handleSuccess(ctx, ctx.View, detection, explanation)
+}
+
+// Upload runs the upload action, the
+// request body is the archive uploaded.
+func (c *BuildToolDetectorController) Upload(ctx *app.UploadBuildToolDetectorContext) error {
+	ctx.ResponseWriter.Header().Set(contentType, applicationJSON)
+
+	detectCtx := ctx.Context
+	var explanation *types.Explanation
+	if ctx.Explain != nil && *ctx.Explain {
+		detectCtx, explanation = types.WithExplanation(detectCtx)
+	}
+
+	var path string
+	if ctx.Path != nil {
+		path = *ctx.Path
+	}
+	body := ctx.Request.Body
+	if body == nil {
+		body = http.NoBody
+	}
+	repositoryService, err := archive.Create(body, path, c.Configuration)
+	if err == archive.ErrArchiveTooLarge || err == archive.ErrTooManyEntries {
+		if writerErr := formatResponse(ctx, ctx.ResponseData, errs.ErrRequestEntityTooLarge(err)); writerErr != nil {
+			return writerErr
+		}
+		return ctx.RequestEntityTooLarge()
 	}
-	buildToolType, err := repositoryService.DetectBuildTool(ctx.Context)
 	if err != nil {
-		return handleError(ctx, err)
+		return handleError(ctx, ctx.ResponseData, err)
+	}
+	// An unknown build tool is still reported.
+	detection, err := repositoryService.DetectBuildTool(detectCtx)
+	if err != nil && err != types.ErrFailedContentRetrieval {
+		return handleError(ctx, ctx.ResponseData, err)
 	}
 
-	buildTool := handleSuccess(*buildToolType)
-	return ctx.OK(buildTool)
+	return handleSuccess(ctx, ctx.View, detection, explanation)
+}
+
+// Scan runs the scan action.
+func (c *BuildToolDetectorController) Scan(ctx *app.ScanBuildToolDetectorContext) error {
+	ctx.ResponseWriter.Header().Set(contentType, applicationJSON)
+
+	repositoryService, err := repository.CreateService(&ctx.Context, ctx.URL, ctx.Branch, ctx.Ref, ctx.Path, c.Configuration)
+	if err != nil {
+		return handleError(ctx, ctx.ResponseData, err)
+	}
+	files, _, err := repositoryService.Contents(ctx.Context)
+	if err != nil {
+		return handleError(ctx, ctx.ResponseData, err)
+	}
+
+	depth := types.DefaultScanDepth
+	if ctx.Depth != nil {
+		depth = *ctx.Depth
+	}
+	roots, err := types.Scan(ctx.Context, files, depth, c.GetDetectorPrecedence())
+	if err != nil {
+		return handleError(ctx, ctx.ResponseData, err)
+	}
+	buildRoots := make(app.GoaBuildRootCollection, len(roots))
+	for i, root := range roots {
+		buildRoots[i] = &app.GoaBuildRoot{
+			Path:          root.Path,
+			BuildToolType: root.BuildType,
+			Evidence:      root.Evidence,
+		}
+	}
+	return ctx.OK(buildRoots)
+}
+
+// detectionResponder is implemented by the contexts
+// of the actions responding with a detection.
+type detectionResponder interface {
+	OK(*app.GoaBuildToolDetector) error
+	OKDetailed(*app.GoaBuildToolDetectorDetailed) error
 }
 
 // handleSuccess handles returning
 // the correct json for 200 OK responses.
-func handleSuccess(buildToolType string) *app.GoaBuildToolDetector {
-	switch buildToolType {
-	case types.Maven:
-		return types.NewMaven()
-	case types.NodeJS:
-		return types.NewNodeJS()
-	case types.Golang:
-		return types.NewGolang()
-	default:
-		return types.NewUnknown()
+// The explanation, when requested, is
+// part of the detailed view.
+func handleSuccess(ctx detectionResponder, view *string, detection *types.Detection, explanation *types.Explanation) error {
+	if view != nil && *view == detailedView {
+		detailed := render.NewDetailed(detection)
+		detailed.Explanation = render.NewExplanation(explanation)
+		return ctx.OKDetailed(detailed)
 	}
+	return ctx.OK(render.NewBuildTool(detection))
+}
+
+// responder is implemented by the contexts
+// of the build-tool-detector actions.
+type responder interface {
+	BadRequest() error
+	NotFound() error
+	InternalServerError() error
 }
 
 // handleError handles returning
 // the correct http responses upon error.
-func handleError(ctx *app.ShowBuildToolDetectorContext, err error) error {
+func handleError(ctx responder, response *goa.ResponseData, err error) error {
+	if _, ok := err.(*types.InvalidOverrideError); ok {
+		httpError := errs.ErrBadRequest(err)
+		writerErr := formatResponse(ctx, response, httpError)
+		if writerErr != nil {
+			return writerErr
+		}
+		return ctx.BadRequest()
+	}
 	switch err.Error() {
-	case github.ErrInvalidPath.Error():
+	case github.ErrInvalidPath.Error(),
+		archive.ErrUnsupportedArchive.Error():
 		httpError := errs.ErrBadRequest(err)
-		writerErr := formatResponse(ctx, httpError)
+		writerErr := formatResponse(ctx, response, httpError)
 		if writerErr != nil {
 			return writerErr
 		}
 		return ctx.BadRequest()
 	case github.ErrResourceNotFound.Error():
 		httpError := errs.ErrNotFoundError(err)
-		writerErr := formatResponse(ctx, httpError)
+		writerErr := formatResponse(ctx, response, httpError)
 		if writerErr != nil {
 			return writerErr
 		}
@@ -106,14 +209,11 @@ func handleError(ctx *app.ShowBuildToolDetectorContext, err error) error {
 	case repository.ErrUnsupportedService.Error(),
 		github.ErrUnsupportedGithubURL.Error():
 		httpError := errs.ErrInternalServerError(err)
-		writerErr := formatResponse(ctx, httpError)
+		writerErr := formatResponse(ctx, response, httpError)
 		if writerErr != nil {
 			return writerErr
 		}
 		return ctx.InternalServerError()
-	case github.ErrFailedContentRetrieval.Error():
-		buildTool := types.NewUnknown()
-		return ctx.OK(buildTool)
 	default:
 		return ctx.InternalServerError()
 	}
@@ -121,15 +221,15 @@ func handleError(ctx *app.ShowBuildToolDetectorContext, err error) error {
 
 // formatResponse writes the header
 // and formats the response.
-func formatResponse(ctx *app.ShowBuildToolDetectorContext, httpTypeError *errs.HTTPTypeError) error {
-	ctx.WriteHeader(httpTypeError.StatusCode)
+func formatResponse(ctx responder, response *goa.ResponseData, httpTypeError *errs.HTTPTypeError) error {
+	response.WriteHeader(httpTypeError.StatusCode)
 	jsonHTTPTypeError, err := json.Marshal(httpTypeError)
 	if err != nil {
 		log.Logger().WithError(err).WithField(errorz, httpTypeError).Errorf(ErrFailedJSONMarshal.Error())
 		return ctx.InternalServerError()
 	}
 
-	if _, err := fmt.Fprint(ctx.ResponseWriter, string(jsonHTTPTypeError)); err != nil {
+	if _, err := fmt.Fprint(response.ResponseWriter, string(jsonHTTPTypeError)); err != nil {
 		log.Logger().WithError(err).WithField(errorz, jsonHTTPTypeError).Errorf(ErrFailedPropagate.Error())
 		return ctx.InternalServerError()
 	}
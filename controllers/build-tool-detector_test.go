@@ -53,7 +53,7 @@ var _ = Describe("BuildToolDetector", func() {
 				BodyString(string(bodyString))
 
 			branch := "master"
-			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcherz/launcher-backend", &branch)
+			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcherz/launcher-backend", &branch, nil, nil, nil, nil)
 		})
 
 		It("Non-existent owner name -- 404 Owner Not Found", func() {
@@ -66,7 +66,7 @@ var _ = Describe("BuildToolDetector", func() {
 				BodyString(string(bodyString))
 
 			branch := "master"
-			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backendz", &branch)
+			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backendz", &branch, nil, nil, nil, nil)
 		})
 
 		It("Non-existent branch name -- 404 Branch Not Found", func() {
@@ -78,21 +78,41 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(404).
 				BodyString(string(bodyString))
 
-			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/masterz", nil)
+			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/masterz", nil, nil, nil, nil, nil)
 		})
 
 		It("Invalid URL -- 400 Bad Request", func() {
 			branch := "master"
-			test.ShowBuildToolDetectorBadRequest(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "fabric8-launcher/launcher-backend", &branch)
+			test.ShowBuildToolDetectorBadRequest(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "fabric8-launcher/launcher-backend", &branch, nil, nil, nil, nil)
+		})
+
+		It("Invalid override file -- 400 Bad Request", func() {
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(`{"tree": [{"path": ".build-tool-detector.yaml", "type": "blob", "sha": "c3d4"}, {"path": "pom.xml", "type": "blob"}], "truncated": false}`)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/blobs/c3d4").
+				Reply(200).
+				BodyString("build-tool: ant\n")
+
+			branch := "master"
+			test.ShowBuildToolDetectorBadRequest(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", &branch, nil, nil, nil, nil)
 		})
 
 		It("Unsupported Git Service -- 500 Internal Server Error", func() {
 			branch := "master"
-			test.ShowBuildToolDetectorInternalServerError(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "http://gitlab.com/fabric8-launcher/launcher-backend", &branch)
+			test.ShowBuildToolDetectorInternalServerError(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "http://gitea.com/fabric8-launcher/launcher-backend", &branch, nil, nil, nil, nil)
 		})
 
 		It("Invalid URL and Branch -- 500 Internal Server Error", func() {
-			test.ShowBuildToolDetectorBadRequest(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "", nil)
+			test.ShowBuildToolDetectorBadRequest(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "", nil, nil, nil, nil, nil)
 		})
 	})
 
@@ -126,14 +146,14 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(200).
 				BodyString(string(bodyString))
 
-			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_wit/not_found_contents.json")
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_wit/unknown_tree.json")
 			Expect(err).Should(BeNil())
 			gock.New("https://api.github.com").
-				Get("/repos/fabric8-services/fabric8-wit/contents/pom.xml").
-				Reply(404).
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
+				Reply(200).
 				BodyString(string(bodyString))
 			branch := "master"
-			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-services/fabric8-wit", &branch)
+			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-services/fabric8-wit", &branch, nil, nil, nil, nil)
 			Expect(buildTool.BuildToolType).Should(Equal("unknown"), "buildTool should not be empty")
 		})
 
@@ -146,14 +166,16 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(200).
 				BodyString(string(bodyString))
 
-			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_wit/not_found_contents.json")
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_wit/unknown_tree.json")
 			Expect(err).Should(BeNil())
 			gock.New("https://api.github.com").
-				Get("/repos/fabric8-services/fabric8-wit/contents/pom.xml").
-				Reply(404).
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
+				Reply(200).
 				BodyString(string(bodyString))
-			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-services/fabric8-wit/tree/master", nil)
+			view := "detailed"
+			_, buildTool := test.ShowBuildToolDetectorOKDetailed(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-services/fabric8-wit/tree/master", nil, nil, nil, nil, &view)
 			Expect(buildTool.BuildToolType).Should(Equal("unknown"), "buildTool should not be empty")
+			Expect(*buildTool.Branch).Should(Equal("master"), "branch should be master")
 		})
 
 		It("Recognize Maven - Branch field populated", func() {
@@ -165,15 +187,16 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(200).
 				BodyString(string(bodyString))
 
-			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_contents.json")
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_tree.json")
 			Expect(err).Should(BeNil())
 			gock.New("https://api.github.com").
-				Get("/repos/fabric8-launcher/launcher-backend/contents/pom.xml").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
 				Reply(200).
 				BodyString(string(bodyString))
 			branch := "master"
-			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", &branch)
+			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", &branch, nil, nil, nil, nil)
 			Expect(buildTool.BuildToolType).Should(Equal("maven"), "buildTool should not be empty")
+			Expect(buildTool.Overridden).Should(BeNil(), "overridden should be omitted when nothing is pinned")
 		})
 
 		It("Recognize Maven - Branch included in URL", func() {
@@ -185,13 +208,13 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(200).
 				BodyString(string(bodyString))
 
-			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_contents.json")
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_tree.json")
 			Expect(err).Should(BeNil())
 			gock.New("https://api.github.com").
-				Get("/repos/fabric8-launcher/launcher-backend/contents/pom.xml").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
 				Reply(200).
 				BodyString(string(bodyString))
-			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master", nil)
+			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master", nil, nil, nil, nil, nil)
 			Expect(buildTool.BuildToolType).Should(Equal("maven"), "buildTool should not be empty")
 		})
 
@@ -204,13 +227,13 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(200).
 				BodyString(string(bodyString))
 
-			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_ui/ok_contents.json")
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_ui/ok_tree.json")
 			Expect(err).Should(BeNil())
 			gock.New("https://api.github.com").
-				Get("/repos/fabric8-ui/fabric8-ui/contents/package.json").
+				Get("/repos/fabric8-ui/fabric8-ui/git/trees/395c7d63f8a0123487d66f3156429404f170a910").
 				Reply(200).
 				BodyString(string(bodyString))
-			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-ui/fabric8-ui/tree/master", nil)
+			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-ui/fabric8-ui/tree/master", nil, nil, nil, nil, nil)
 			Expect(buildTool.BuildToolType).Should(Equal("nodejs"), "buildTool should be nodejs")
 		})
 
@@ -223,15 +246,229 @@ var _ = Describe("BuildToolDetector", func() {
 				Reply(200).
 				BodyString(string(bodyString))
 
-			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_wit/ok_contents.json")
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_wit/ok_tree.json")
 			Expect(err).Should(BeNil())
 			gock.New("https://api.github.com").
-				Get("/repos/fabric8-services/fabric8-wit/contents/main.go").
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
 				Reply(200).
 				BodyString(string(bodyString))
-			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-services/fabric8-wit/tree/master", nil)
+			_, buildTool := test.ShowBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-services/fabric8-wit/tree/master", nil, nil, nil, nil, nil)
 			Expect(buildTool.BuildToolType).Should(Equal("golang"), "buildTool should be golang")
 		})
 
+		It("Recognize Gradle - Kotlin DSL with wrapper", func() {
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_repo.json")
+			Expect(err).Should(BeNil())
+
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend$").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_tree_gradle.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(string(bodyString))
+			view := "detailed"
+			_, buildTool := test.ShowBuildToolDetectorOKDetailed(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", nil, nil, nil, nil, &view)
+			Expect(buildTool.BuildToolType).Should(Equal("gradle"), "buildTool should be gradle")
+			Expect(*buildTool.Branch).Should(Equal("master"), "default branch should be master")
+			Expect(buildTool.Gradle.Dsl).Should(Equal("kotlin"), "gradle dsl should be kotlin")
+			Expect(buildTool.Gradle.Wrapper).Should(BeTrue(), "gradle wrapper should be present")
+		})
+
+		It("Recognize Maven and NodeJS - Detailed view", func() {
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_repo.json")
+			Expect(err).Should(BeNil())
+
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend$").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_tree_polyglot.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(string(bodyString))
+			view := "detailed"
+			_, buildTool := test.ShowBuildToolDetectorOKDetailed(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", nil, nil, nil, nil, &view)
+			Expect(buildTool.BuildToolType).Should(Equal("maven"), "buildTool should be maven")
+			Expect(*buildTool.Branch).Should(Equal("master"), "default branch should be master")
+			Expect(buildTool.BuildTools).Should(HaveLen(2), "maven and nodejs should be detected")
+			Expect(buildTool.BuildTools[1].BuildToolType).Should(Equal("nodejs"), "second buildTool should be nodejs")
+			Expect(buildTool.BuildTools[1].Evidence).Should(Equal("package.json"), "evidence should be package.json")
+		})
+
+		It("Recognize Maven and NodeJS - Explain", func() {
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_repo.json")
+			Expect(err).Should(BeNil())
+
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend$").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_tree_polyglot.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(string(bodyString))
+			explain := true
+			view := "detailed"
+			_, buildTool := test.ShowBuildToolDetectorOKDetailed(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", nil, &explain, nil, nil, &view)
+			Expect(buildTool.BuildToolType).Should(Equal("maven"), "buildTool should be maven")
+			Expect(buildTool.Explanation).ShouldNot(BeNil(), "explanation should be returned")
+			Expect(buildTool.Explanation.Requests[1].Status).Should(Equal(200), "branch status should be returned")
+			Expect(buildTool.Explanation.Probes[0].BuildToolType).Should(Equal("maven"), "maven should be probed first")
+			Expect(buildTool.Explanation.Probes[0].Matched).Should(BeTrue(), "maven should be matched")
+			Expect(buildTool.Explanation.Decision).Should(Equal("maven takes precedence over nodejs having the highest detector priority"))
+		})
+
+		It("Recognize NodeJS - Path", func() {
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(`{"tree": [{"path": "pom.xml", "type": "blob"}, {"path": "frontend/package.json", "type": "blob"}], "truncated": false}`)
+			path := "frontend"
+			view := "detailed"
+			_, buildTool := test.ShowBuildToolDetectorOKDetailed(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master", nil, nil, &path, nil, &view)
+			Expect(buildTool.BuildToolType).Should(Equal("nodejs"), "buildTool should be nodejs")
+			Expect(*buildTool.Path).Should(Equal("frontend"), "path should be frontend")
+		})
+
+		It("Path not found", func() {
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(`{"tree": [{"path": "pom.xml", "type": "blob"}], "truncated": false}`)
+			test.ShowBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master/backend", nil, nil, nil, nil, nil)
+		})
+
+		It("Recognize Maven - Release tag ref", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/commits/v1.0.0").
+				Reply(200).
+				BodyString("a2eb145933e1044956aa96fac4945be37970ed19")
+
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_tree.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(string(bodyString))
+			ref := "v1.0.0"
+			view := "detailed"
+			_, buildTool := test.ShowBuildToolDetectorOKDetailed(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend", nil, nil, nil, &ref, &view)
+			Expect(buildTool.BuildToolType).Should(Equal("maven"), "buildTool should be maven")
+			Expect(buildTool.Branch).Should(BeNil(), "no branch should be analyzed")
+			Expect(*buildTool.Ref).Should(Equal("v1.0.0"), "ref should be v1.0.0")
+			Expect(*buildTool.Commit).Should(Equal("a2eb145933e1044956aa96fac4945be37970ed19"), "commit of the tag should be analyzed")
+		})
+	})
+
+	Context("Scan", func() {
+		var service *goa.Service
+		var configuration *config.Configuration
+
+		BeforeEach(func() {
+			service = goa.New("build-tool-detector")
+			// Mock auth service with success response
+			authBodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_auth_backend/return_token.json")
+			Expect(err).Should(BeNil())
+
+			configuration = config.New()
+			gock.New(configuration.GetAuthServiceURL()).
+				Get("/api/token").
+				Reply(200).
+				BodyString(string(authBodyString))
+
+			bodyString, err := ioutil.ReadFile("../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(`{"tree": [{"path": "pom.xml", "type": "blob"}, {"path": "frontend/package.json", "type": "blob"}, {"path": "frontend/node_modules/left-pad/package.json", "type": "blob"}, {"path": "services/api/go.mod", "type": "blob"}], "truncated": false}`)
+		})
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Build roots", func() {
+			_, buildRoots := test.ScanBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master", nil, nil, nil, nil)
+			Expect(buildRoots).Should(HaveLen(3), "root, frontend and services/api should be build roots")
+			Expect(buildRoots[1].Path).Should(Equal("frontend"), "path should be frontend")
+			Expect(buildRoots[1].BuildToolType).Should(Equal("nodejs"), "buildTool should be nodejs")
+			Expect(buildRoots[1].Evidence).Should(Equal("frontend/package.json"), "evidence should be frontend/package.json")
+		})
+
+		It("Build roots - depth", func() {
+			depth := 1
+			_, buildRoots := test.ScanBuildToolDetectorOK(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master", nil, &depth, nil, nil)
+			Expect(buildRoots).Should(HaveLen(2), "services/api should be too deep")
+		})
+
+		It("Path not found -- 404 Not Found", func() {
+			path := "backend"
+			test.ScanBuildToolDetectorNotFound(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), "https://github.com/fabric8-launcher/launcher-backend/tree/master", nil, nil, &path, nil)
+		})
+	})
+
+	Context("Upload", func() {
+		var service *goa.Service
+		var configuration *config.Configuration
+
+		BeforeEach(func() {
+			service = goa.New("build-tool-detector")
+			configuration = config.New()
+		})
+
+		It("Not an archive -- 400 Bad Request", func() {
+			test.UploadBuildToolDetectorBadRequest(GinkgoT(), nil, nil, controllers.NewBuildToolDetectorController(service, *configuration), nil, nil, nil)
+		})
 	})
 })
@@ -49,3 +49,13 @@ func ErrNotFoundError(err error) *HTTPTypeError {
 		Error:         err.Error(),
 	}
 }
+
+// ErrRequestEntityTooLarge request entity too large error.
+func ErrRequestEntityTooLarge(err error) *HTTPTypeError {
+
+	return &HTTPTypeError{
+		StatusCode:    http.StatusRequestEntityTooLarge,
+		StatusMessage: http.StatusText(http.StatusRequestEntityTooLarge),
+		Error:         err.Error(),
+	}
+}
@@ -36,4 +36,11 @@ var _ = Describe("Error", func() {
 			Expect(notfound.StatusCode).Should(BeEquivalentTo(http.StatusNotFound), "service type should be 'nil'")
 		})
 	})
+
+	Context("ErrRequestEntityTooLarge", func() {
+		It("Set ErrRequestEntityTooLarge", func() {
+			tooLarge := ErrRequestEntityTooLarge(errors.New("request entity too large"))
+			Expect(tooLarge.StatusCode).Should(BeEquivalentTo(http.StatusRequestEntityTooLarge), "status code should be 413")
+		})
+	})
 })
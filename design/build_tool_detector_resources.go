@@ -25,12 +25,58 @@ var _ = a.Resource("build-tool-detector", func() {
 		a.Params(func() {
 			a.Param("url", d.String, "repository url")
 			a.Param("branch", d.String, "repository branch")
+			a.Param("ref", d.String, "repository ref, a branch, tag, commit sha or pull request ref, takes precedence over the branch")
+			a.Param("path", d.String, "directory of the repository the build tools are detected in")
+			a.Param("view", d.String, "response view, detailed lists every detected build tool with the details of the detection", func() {
+				a.Enum("default", "detailed")
+			})
+			a.Param("explain", d.Boolean, "whether to explain how the build tool was detected, in the detailed view")
 		})
 		a.Response(d.OK)
 		a.Response(d.InternalServerError)
 		a.Response(d.BadRequest)
 		a.Response(d.NotFound)
 	})
+	a.Action("upload", func() {
+		a.Security("jwt")
+		a.Description("Detects the build tool of an uploaded zip or tar.gz archive of the sources.")
+		a.Routing(
+			a.POST("/build"),
+		)
+		a.Params(func() {
+			a.Param("path", d.String, "directory of the archive the build tools are detected in")
+			a.Param("view", d.String, "response view, detailed lists every detected build tool with the details of the detection", func() {
+				a.Enum("default", "detailed")
+			})
+			a.Param("explain", d.Boolean, "whether to explain how the build tool was detected, in the detailed view")
+		})
+		a.Response(d.OK)
+		a.Response(d.InternalServerError)
+		a.Response(d.BadRequest)
+		a.Response(d.NotFound)
+		a.Response(d.RequestEntityTooLarge)
+	})
+	a.Action("scan", func() {
+		a.Security("jwt")
+		a.Description("Lists every directory of a given repository which is the root of a build.")
+		a.Routing(
+			a.GET("/scan/:url"),
+		)
+		a.Params(func() {
+			a.Param("url", d.String, "repository url")
+			a.Param("branch", d.String, "repository branch")
+			a.Param("ref", d.String, "repository ref, a branch, tag, commit sha or pull request ref, takes precedence over the branch")
+			a.Param("path", d.String, "directory of the repository the scan starts at")
+			a.Param("depth", d.Integer, "depth of the directories scanned, defaults to 3", func() {
+				a.Minimum(0)
+				a.Maximum(10)
+			})
+		})
+		a.Response(d.OK, a.CollectionOf(BuildRootMedia))
+		a.Response(d.InternalServerError)
+		a.Response(d.BadRequest)
+		a.Response(d.NotFound)
+	})
 })
 
 // BuildToolDetectorMedia defines the media type used to render the build tool
@@ -38,9 +84,243 @@ var BuildToolDetectorMedia = a.MediaType("application/vnd.goa.build.tool.detecto
 	a.Description("Detected build tool type.")
 	a.Attributes(func() {
 		a.Attribute("build-tool-type", d.String, "Name of build tool")
+		a.Attribute("runtime", RuntimeType, "Language runtime of the build tool of highest precedence")
+		a.Attribute("branch", d.String, "Branch the build tools were detected on")
+		a.Attribute("ref", d.String, "Ref the build tools were detected at, if not a branch")
+		a.Attribute("commit", d.String, "Sha of the commit the build tools were detected at")
+		a.Attribute("path", d.String, "Directory the build tools were detected in, if not the root")
+		a.Attribute("overridden", d.Boolean, "Whether the results are pinned by the override file of the repository, omitted if not")
+		a.Attribute("builder-image", d.String, "Builder image pinned by the override file of the repository")
+		a.Attribute("build-tools", a.ArrayOf(DetectedBuildToolMedia), "Every build tool detected, ordered by precedence")
+		a.Attribute("framework", FrameworkType, "Java framework the application is built on")
+		a.Attribute("maven", MavenType, "Details of the maven build")
+		a.Attribute("gradle", GradleType, "Details of the gradle build")
+		a.Attribute("nodejs", NodeJSType, "Details of the node project")
+		a.Attribute("python", PythonType, "Details of the python project")
+		a.Attribute("golang", GolangType, "Details of the go project")
+		a.Attribute("explanation", ExplanationType, "How the build tool was detected, when requested")
 		a.Required("build-tool-type")
 	})
 	a.View("default", func() {
 		a.Attribute("build-tool-type")
+		a.Attribute("overridden")
+	})
+	a.View("detailed", func() {
+		a.Attribute("build-tool-type")
+		a.Attribute("runtime")
+		a.Attribute("branch")
+		a.Attribute("ref")
+		a.Attribute("commit")
+		a.Attribute("path")
+		a.Attribute("overridden")
+		a.Attribute("builder-image")
+		a.Attribute("framework")
+		a.Attribute("maven")
+		a.Attribute("gradle")
+		a.Attribute("nodejs")
+		a.Attribute("python")
+		a.Attribute("golang")
+		a.Attribute("build-tools")
+		a.Attribute("explanation")
+	})
+})
+
+// DetectedBuildToolMedia defines the media type used to render
+// a single build tool detected within the repository
+var DetectedBuildToolMedia = a.MediaType("application/vnd.goa.detected.build.tool+json", func() {
+	a.Description("Build tool detected within the repository.")
+	a.Attributes(func() {
+		a.Attribute("build-tool-type", d.String, "Name of build tool")
+		a.Attribute("confidence", d.Number, "Confidence of the detection, between 0 and 1")
+		a.Attribute("evidence", d.String, "Path of the file the build tool was detected by")
+		a.Attribute("runtime", RuntimeType, "Language runtime of the build tool")
+		a.Attribute("framework", FrameworkType, "Java framework the application is built on")
+		a.Attribute("maven", MavenType, "Details of the maven build")
+		a.Attribute("gradle", GradleType, "Details of the gradle build")
+		a.Attribute("nodejs", NodeJSType, "Details of the node project")
+		a.Attribute("python", PythonType, "Details of the python project")
+		a.Attribute("golang", GolangType, "Details of the go project")
+		a.Required("build-tool-type", "confidence", "evidence")
+	})
+	a.View("default", func() {
+		a.Attribute("build-tool-type")
+		a.Attribute("confidence")
+		a.Attribute("evidence")
+		a.Attribute("runtime")
+		a.Attribute("framework")
+		a.Attribute("maven")
+		a.Attribute("gradle")
+		a.Attribute("nodejs")
+		a.Attribute("python")
+		a.Attribute("golang")
+	})
+})
+
+// BuildRootMedia defines the media type used to render
+// a directory of the repository which is a build root
+var BuildRootMedia = a.MediaType("application/vnd.goa.build.root+json", func() {
+	a.Description("Directory of the repository which is the root of a build.")
+	a.Attributes(func() {
+		a.Attribute("path", d.String, "Path of the directory, relative to the repository root")
+		a.Attribute("build-tool-type", d.String, "Name of build tool")
+		a.Attribute("evidence", d.String, "Path of the file the build tool was detected by")
+		a.Required("path", "build-tool-type", "evidence")
+	})
+	a.View("default", func() {
+		a.Attribute("path")
+		a.Attribute("build-tool-type")
+		a.Attribute("evidence")
+	})
+})
+
+// RuntimeType defines the language
+// runtime a build requires
+var RuntimeType = a.Type("Runtime", func() {
+	a.Description("Language runtime a build requires.")
+	a.Attribute("name", d.String, "Name of the runtime", func() {
+		a.Enum("java", "node", "go", "python", "ruby", "rust")
+	})
+	a.Attribute("version", d.String, "Version, or version constraint, declared by the project")
+	a.Required("name")
+})
+
+// FrameworkType defines the java framework
+// an application is built on
+var FrameworkType = a.Type("Framework", func() {
+	a.Description("Java framework an application is built on.")
+	a.Attribute("name", d.String, "Name of the framework", func() {
+		a.Enum("spring-boot", "quarkus", "vertx", "thorntail", "wildfly")
+	})
+	a.Attribute("version", d.String, "Version of the framework, if resolved")
+	a.Required("name")
+})
+
+// MavenType defines the details reported for maven builds
+var MavenType = a.Type("Maven", func() {
+	a.Description("Details of a maven build.")
+	a.Attribute("group-id", d.String, "Group id of the root module, or of its parent")
+	a.Attribute("artifact-id", d.String, "Artifact id of the root module")
+	a.Attribute("version", d.String, "Version of the root module, or of its parent")
+	a.Attribute("packaging", d.String, "Packaging of the root module")
+	a.Attribute("deployable", d.Boolean, "Whether the root module packages a deployable application")
+	a.Attribute("modules", a.ArrayOf(d.String), "Paths of the modules declared by the root module")
+	a.Attribute("reactor", a.ArrayOf(MavenModuleType), "Every module of the reactor, depth first with each parent before its modules")
+	a.Required("artifact-id", "packaging", "deployable")
+})
+
+// MavenModuleType defines the details reported for
+// a module of a maven reactor
+var MavenModuleType = a.Type("MavenModule", func() {
+	a.Description("Module of a maven reactor.")
+	a.Attribute("path", d.String, "Directory of the module")
+	a.Attribute("group-id", d.String, "Group id of the module, or of its parent")
+	a.Attribute("artifact-id", d.String, "Artifact id of the module")
+	a.Attribute("version", d.String, "Version of the module, or of its parent")
+	a.Attribute("packaging", d.String, "Packaging of the module, war and ear are deployable")
+	a.Attribute("deployable", d.Boolean, "Whether the module packages a deployable application, as opposed to a library")
+	a.Attribute("modules", a.ArrayOf(d.String), "Paths of the modules declared by the module")
+	a.Required("path", "artifact-id", "packaging", "deployable")
+})
+
+// GradleType defines the details reported for gradle builds
+var GradleType = a.Type("Gradle", func() {
+	a.Description("Details of a gradle build.")
+	a.Attribute("dsl", d.String, "Language of the build scripts", func() {
+		a.Enum("groovy", "kotlin")
+	})
+	a.Attribute("wrapper", d.Boolean, "Whether the gradle wrapper is present")
+	a.Required("dsl", "wrapper")
+})
+
+// NodeJSType defines the details reported for node projects
+var NodeJSType = a.Type("NodeJS", func() {
+	a.Description("Details of a node project.")
+	a.Attribute("package-manager", d.String, "Package manager the dependencies are installed with", func() {
+		a.Enum("npm", "yarn", "pnpm")
+	})
+	a.Attribute("node-version", d.String, "Node version constraint declared by engines.node")
+	a.Attribute("scripts", a.ArrayOf(d.String), "Build, start and test scripts declared by package.json")
+	a.Attribute("typescript", d.Boolean, "Whether the project is written in typescript")
+	a.Attribute("frameworks", a.ArrayOf(d.String), "Frameworks the project depends on")
+	a.Attribute("workspaces", NodeWorkspacesType, "Workspaces of the monorepo")
+	a.Required("package-manager", "typescript")
+})
+
+// NodeWorkspacesType defines the workspaces
+// reported for javascript monorepos
+var NodeWorkspacesType = a.Type("NodeWorkspaces", func() {
+	a.Description("Workspaces of a javascript monorepo.")
+	a.Attribute("tool", d.String, "Tool orchestrating the workspaces", func() {
+		a.Enum("npm", "yarn", "pnpm", "lerna", "nx", "turborepo")
+	})
+	a.Attribute("packages", a.ArrayOf(d.String), "Directories of the workspace packages")
+	a.Required("tool", "packages")
+})
+
+// PythonType defines the details reported for python projects
+var PythonType = a.Type("Python", func() {
+	a.Description("Details of a python project.")
+	a.Attribute("packaging-tool", d.String, "Tool used to package the project", func() {
+		a.Enum("pip", "setuptools", "poetry", "flit", "hatch", "pdm", "pipenv")
+	})
+	a.Attribute("build-backend", d.String, "PEP 517 build backend declared in pyproject.toml")
+	a.Required("packaging-tool")
+})
+
+// GolangType defines the details reported for go projects
+var GolangType = a.Type("Golang", func() {
+	a.Description("Details of a go project.")
+	a.Attribute("module", d.String, "Module path, or glide package")
+	a.Attribute("go-version", d.String, "Go directive of the go.mod file")
+	a.Attribute("dependency-manager", d.String, "Tool managing the dependencies", func() {
+		a.Enum("modules", "dep", "glide")
+	})
+	a.Attribute("main-packages", a.ArrayOf(d.String), "Main packages found under cmd/")
+})
+
+// ExplanationType defines how the build
+// tool of a repository was detected
+var ExplanationType = a.Type("Explanation", func() {
+	a.Description("How the build tool of a repository was detected.")
+	a.Attribute("requests", a.ArrayOf(ServiceRequestType), "Requests made to the git service, in order")
+	a.Attribute("probes", a.ArrayOf(ProbeType), "Marker files probed for every registered build tool, by precedence")
+	a.Attribute("override", d.String, "Override file read from the root of the repository")
+	a.Attribute("decision", d.String, "Precedence decision taken among the build tools matched")
+	a.Required("requests", "probes", "decision")
+})
+
+// ServiceRequestType defines a request
+// made to the git service
+var ServiceRequestType = a.Type("ServiceRequest", func() {
+	a.Description("Request made to the git service.")
+	a.Attribute("method", d.String, "Method of the request")
+	a.Attribute("url", d.String, "Url of the request")
+	a.Attribute("status", d.Integer, "Http status received, 0 when no response was received")
+	a.Attribute("error", d.String, "Error of the request, if no response was received")
+	a.Required("method", "url", "status")
+})
+
+// ProbeType defines the marker files
+// probed for a build tool
+var ProbeType = a.Type("Probe", func() {
+	a.Description("Marker files probed for a build tool.")
+	a.Attribute("build-tool-type", d.String, "Name of build tool")
+	a.Attribute("precedence", d.Integer, "Position of the build tool in the order the detectors are evaluated, from 1")
+	a.Attribute("files", a.ArrayOf(FileProbeType), "Result of probing every marker file")
+	a.Attribute("matched", d.Boolean, "Whether the build tool was matched")
+	a.Attribute("reason", d.String, "Why the build tool was matched or not")
+	a.Required("build-tool-type", "precedence", "files", "matched", "reason")
+})
+
+// FileProbeType defines the result
+// of probing a marker file
+var FileProbeType = a.Type("FileProbe", func() {
+	a.Description("Result of probing a marker file.")
+	a.Attribute("pattern", d.String, "Marker file, or pattern, of the detector")
+	a.Attribute("file", d.String, "File of the repository matching the pattern")
+	a.Attribute("result", d.String, "Result of the probe", func() {
+		a.Enum("matched", "missing", "rejected", "failed")
 	})
+	a.Attribute("error", d.String, "Error evaluating the predicates of the detector")
+	a.Required("pattern", "result")
 })
@@ -0,0 +1,450 @@
+/*
+
+Package giturl implements the parsing of the
+many forms of repository urls, web urls as well
+as clone urls, into a normalized value made of
+the host, owner, repository, ref and subpath.
+
+*/
+package giturl
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+// The type of the ref the url points at, refs
+// without a type are branches, tags or commits.
+const (
+	Branch      = "branch"
+	Tag         = "tag"
+	Commit      = "commit"
+	PullRequest = "pull-request"
+)
+
+const (
+	slash     = "/"
+	separator = "-"
+	dot       = "."
+	gitSuffix = ".git"
+	schemeSep = "://"
+	https     = "https"
+	http      = "http"
+
+	tree     = "tree"
+	blob     = "blob"
+	src      = "src"
+	commit   = "commit"
+	commits  = "commits"
+	releases = "releases"
+	tag      = "tag"
+	tags     = "tags"
+
+	pull          = "pull"
+	pullRequests  = "pull-requests"
+	mergeRequests = "merge_requests"
+
+	projects   = "projects"
+	users      = "users"
+	repos      = "repos"
+	browse     = "browse"
+	scm        = "scm"
+	userPrefix = "~"
+	at         = "at"
+	refsHeads  = "refs/heads/"
+	refsTags   = "refs/tags/"
+)
+
+var (
+	// ErrInvalidURL the url is not
+	// a repository url.
+	ErrInvalidURL = types.ErrInvalidPath
+
+	// scpLike matches clone urls
+	// as in git@github.com:owner/repo.git.
+	scpLike = regexp.MustCompile(`^[\w.-]+@([\w.-]+):/?(.+)$`)
+
+	// refSegments are the path segments
+	// following the repository which
+	// point at a ref.
+	refSegments = map[string]bool{
+		tree:          true,
+		blob:          true,
+		commit:        true,
+		commits:       true,
+		tags:          true,
+		mergeRequests: true,
+	}
+
+	// cloneSchemes are the schemes of clone
+	// urls, which are normalized to https.
+	cloneSchemes = map[string]bool{
+		"ssh":     true,
+		"git":     true,
+		"git+ssh": true,
+		"ssh+git": true,
+	}
+)
+
+// URL is a repository url normalized to the host,
+// owner and repository, along with the ref and
+// the subpath the url points at, if any.
+type URL struct {
+	Scheme     string
+	Host       string
+	Owner      string
+	Repository string
+	Ref        string
+	RefType    string
+	Subpath    string
+
+	// refPath holds the segments following a
+	// branch, which are split into the ref and
+	// the subpath as branches may contain slashes.
+	refPath []string
+
+	// dir holds the directory set through WithPath,
+	// it takes precedence over the subpath of the url.
+	dir      string
+	segments []string
+	clone    bool
+	prefix   string
+	web      *url.URL
+}
+
+// Parse parses the repository url. Web urls, with
+// /tree/, /blob/, /commit/, /releases/tag/ or pull
+// request paths, as well as ssh, git and scp-like
+// clone urls are supported, as well as the bitbucket
+// server /projects/KEY/repos/slug/browse?at=ref urls.
+// Bitbucket server clone urls are parsed by Server.
+// The url is invalid if it has no host.
+func Parse(rawURL string) (*URL, error) {
+	u, clone, err := normalize(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := URL{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		segments: split(u.Path),
+		clone:    clone,
+		web:      u,
+	}
+
+	segments := parsed.segments
+	if i := serverProject(segments); i >= 0 {
+		parsed.Owner = segments[i+1]
+		if segments[i] == users {
+			parsed.Owner = userPrefix + parsed.Owner
+		}
+		parsed.Repository = segments[i+3]
+		if i > 0 {
+			parsed.prefix = slash + strings.Join(segments[:i], slash)
+		}
+		parsed.parseServerRef(segments[i+4:], u.Query().Get(at))
+		return &parsed, nil
+	}
+
+	parsed.parseProject(false)
+	return &parsed, nil
+}
+
+// Nested returns the url with the repository
+// preceded by nested groups, as in gitlab
+// https://gitlab.com/group/subgroup/project.
+func (u URL) Nested() *URL {
+	if u.prefix != "" || serverProject(u.segments) >= 0 {
+		return &u
+	}
+	u.parseProject(true)
+	return &u
+}
+
+// Server returns the url with the project key and
+// the slug of bitbucket server clone urls, as in
+// https://host/scm/KEY/slug.git, the scm segment
+// being preceded by the context path if any.
+func (u URL) Server() *URL {
+	if u.prefix != "" || serverProject(u.segments) >= 0 {
+		return &u
+	}
+	for i, segment := range u.segments {
+		if segment == scm && i+3 == len(u.segments) {
+			u.Owner = u.segments[i+1]
+			u.Repository = u.segments[i+2]
+			if i > 0 {
+				u.prefix = slash + strings.Join(u.segments[:i], slash)
+			}
+			return &u
+		}
+	}
+	return &u
+}
+
+// WithBranch returns the url pointing
+// at the branch of the repository.
+func (u URL) WithBranch(branch string) *URL {
+	u.Ref = branch
+	u.RefType = Branch
+	u.refPath = nil
+	return &u
+}
+
+// WithRef returns the url pointing at the ref of
+// the repository, a branch, tag or commit sha.
+func (u URL) WithRef(ref string) *URL {
+	u.Ref = ref
+	u.RefType = ""
+	u.refPath = nil
+	return &u
+}
+
+// WithPath returns the url pointing at the
+// directory of the repository, whichever the
+// subpath of the url.
+func (u URL) WithPath(dir string) *URL {
+	u.dir = strings.Trim(dir, slash)
+	u.Subpath = u.dir
+	return &u
+}
+
+// SubpathOf returns the subpath of the url when
+// pointing at the branch, as the split of branches
+// containing slashes determines the subpath.
+func (u URL) SubpathOf(branch string) string {
+	for _, split := range u.Splits() {
+		if split.Ref == branch {
+			return split.Subpath
+		}
+	}
+	return u.Subpath
+}
+
+// Project returns the path of the
+// repository, the owner followed
+// by the repository name.
+func (u URL) Project() string {
+	return u.Owner + slash + u.Repository
+}
+
+// BaseURL returns the url of the git service,
+// including the context path bitbucket server
+// can be deployed with.
+func (u URL) BaseURL() string {
+	return u.Scheme + schemeSep + u.Host + u.prefix
+}
+
+// WebURL returns the normalized web url of
+// the repository, clone urls are converted
+// to https and the .git suffix is removed.
+func (u URL) WebURL() *url.URL {
+	web := *u.web
+	return &web
+}
+
+// Candidates returns the candidate branches of the
+// splits, shortest first. Urls not pointing at a
+// branch have no candidates.
+func (u URL) Candidates() []string {
+	if u.RefType != Branch || u.Ref == "" {
+		return nil
+	}
+
+	var candidates []string
+	for _, split := range u.Splits() {
+		candidates = append(candidates, split.Ref)
+	}
+	return candidates
+}
+
+// Splits returns the possible splits of the ref and
+// subpath, shortest ref first, as branches may contain
+// slashes. Urls not pointing at a branch have a
+// single split.
+func (u URL) Splits() []URL {
+	if len(u.refPath) < 2 {
+		return []URL{u}
+	}
+
+	splits := make([]URL, len(u.refPath))
+	for i := range u.refPath {
+		splits[i] = u
+		splits[i].Ref = strings.Join(u.refPath[:i+1], slash)
+		splits[i].Subpath = strings.Join(u.refPath[i+1:], slash)
+		if u.dir != "" {
+			splits[i].Subpath = u.dir
+		}
+	}
+	return splits
+}
+
+// parseProject sets the owner and repository, and the
+// ref the url points at, from the path segments.
+func (u *URL) parseProject(nested bool) {
+	u.Owner, u.Repository, u.Ref, u.RefType, u.Subpath, u.refPath = "", "", "", "", "", nil
+
+	project, rest := splitProject(u.segments, u.clone, nested)
+	if len(project) > 0 {
+		u.Owner = strings.Join(project[:len(project)-1], slash)
+		u.Repository = project[len(project)-1]
+	}
+	if u.Owner == "" {
+		u.Owner, u.Repository = u.Repository, ""
+	}
+	u.parseRef(rest)
+}
+
+// parseRef sets the ref the url
+// points at from the path segments
+// following the repository.
+func (u *URL) parseRef(rest []string) {
+	if len(rest) < 2 {
+		return
+	}
+
+	switch rest[0] {
+	case tree, blob, src:
+		u.refPath = rest[1:]
+		u.Ref = rest[1]
+		u.RefType = Branch
+		u.Subpath = strings.Join(rest[2:], slash)
+	case commit, commits:
+		u.Ref = rest[1]
+		u.RefType = Commit
+	case tags:
+		u.Ref = strings.Join(rest[1:], slash)
+		u.RefType = Tag
+	case pull, pullRequests, mergeRequests:
+		u.Ref = rest[1]
+		u.RefType = PullRequest
+	case releases:
+		if len(rest) > 2 && rest[1] == tag {
+			u.Ref = strings.Join(rest[2:], slash)
+			u.RefType = Tag
+		}
+	}
+}
+
+// parseServerRef sets the ref the url points at
+// from the path segments following the repository
+// and the 'at' query parameter of bitbucket
+// server urls.
+func (u *URL) parseServerRef(rest []string, ref string) {
+	if len(rest) > 1 && rest[0] == commits {
+		u.Ref = rest[1]
+		u.RefType = Commit
+		return
+	}
+	if len(rest) > 1 && rest[0] == pullRequests {
+		u.Ref = rest[1]
+		u.RefType = PullRequest
+		return
+	}
+	if len(rest) > 0 && rest[0] == browse {
+		u.Subpath = strings.Join(rest[1:], slash)
+	}
+
+	switch {
+	case strings.HasPrefix(ref, refsTags):
+		u.Ref = strings.TrimPrefix(ref, refsTags)
+		u.RefType = Tag
+	case ref != "":
+		u.Ref = strings.TrimPrefix(ref, refsHeads)
+		u.RefType = Branch
+	}
+}
+
+// serverProject returns the index of the projects
+// or users segment of bitbucket server urls, which
+// can be preceded by a context path, -1 otherwise.
+func serverProject(segments []string) int {
+	for i := 0; i+3 < len(segments); i++ {
+		if (segments[i] == projects || segments[i] == users) && segments[i+2] == repos {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalize parses the url, converting clone urls to
+// https and removing the .git suffix. Whether the
+// url is a clone url is returned along with it.
+func normalize(rawURL string) (*url.URL, bool, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, schemeSep) {
+		if match := scpLike.FindStringSubmatch(rawURL); match != nil {
+			rawURL = "ssh" + schemeSep + match[1] + slash + match[2]
+		} else if host := strings.SplitN(rawURL, slash, 2)[0]; strings.Contains(host, dot) {
+			rawURL = https + schemeSep + rawURL
+		}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, false, ErrInvalidURL
+	}
+
+	clone := cloneSchemes[strings.ToLower(u.Scheme)]
+	switch {
+	case clone:
+		u.Scheme = https
+		u.Host = u.Hostname()
+	case strings.EqualFold(u.Scheme, http), strings.EqualFold(u.Scheme, https):
+		u.Scheme = strings.ToLower(u.Scheme)
+	default:
+		return nil, false, ErrInvalidURL
+	}
+
+	u.User = nil
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = slash + strings.Join(split(u.Path), slash)
+	u.RawPath = ""
+	if strings.HasSuffix(u.Path, gitSuffix) {
+		u.Path = strings.TrimSuffix(u.Path, gitSuffix)
+		clone = true
+	}
+	return u, clone, nil
+}
+
+// splitProject splits the path segments into the
+// project, the owner followed by the repository, and
+// the remaining segments. Nested groups are supported
+// in clone urls, before the '-' separator and, if
+// nested, before the first segment pointing at a ref.
+func splitProject(segments []string, clone bool, nested bool) ([]string, []string) {
+	for i, segment := range segments {
+		if segment == separator {
+			return segments[:i], segments[i+1:]
+		}
+	}
+	if clone || len(segments) <= 2 {
+		return segments, nil
+	}
+	if !nested {
+		return segments[:2], segments[2:]
+	}
+	for i := 2; i < len(segments); i++ {
+		if refSegments[segments[i]] {
+			return segments[:i], segments[i:]
+		}
+	}
+	return segments, nil
+}
+
+// split returns the non empty
+// segments of the path.
+func split(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, slash) {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
@@ -0,0 +1,19 @@
+/*
+
+Package giturl_test is used to test the functionality
+within the giturl package.
+
+*/
+package giturl_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGiturl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Giturl Suite")
+}
@@ -0,0 +1,223 @@
+/*
+
+Package giturl_test is used to test the functionality
+within the giturl package.
+
+*/
+package giturl_test
+
+import (
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Giturl", func() {
+
+	Context("Parse - clone urls", func() {
+		It("Scp-like", func() {
+			u, err := giturl.Parse("git@github.com:fabric8-launcher/launcher-backend.git")
+			Expect(err).Should(BeNil())
+			Expect(u.Scheme).Should(Equal("https"), "scheme should be 'https'")
+			Expect(u.Host).Should(Equal("github.com"), "host should be 'github.com'")
+			Expect(u.Owner).Should(Equal("fabric8-launcher"), "owner should be 'fabric8-launcher'")
+			Expect(u.Repository).Should(Equal("launcher-backend"), "repository should be 'launcher-backend'")
+			Expect(u.WebURL().String()).Should(Equal("https://github.com/fabric8-launcher/launcher-backend"), "web url should be https")
+		})
+
+		It("Ssh with port", func() {
+			u, err := giturl.Parse("ssh://git@bitbucket.example.com:7999/key/slug.git")
+			Expect(err).Should(BeNil())
+			Expect(u.Host).Should(Equal("bitbucket.example.com"), "port should be removed")
+			Expect(u.Owner).Should(Equal("key"), "owner should be 'key'")
+			Expect(u.Repository).Should(Equal("slug"), "repository should be 'slug'")
+		})
+
+		It("Git - nested groups", func() {
+			u, err := giturl.Parse("git://gitlab.com/group/subgroup/project.git")
+			Expect(err).Should(BeNil())
+			Expect(u.Owner).Should(Equal("group/subgroup"), "owner should be 'group/subgroup'")
+			Expect(u.Repository).Should(Equal("project"), "repository should be 'project'")
+		})
+
+		It("Https with .git suffix and trailing slash", func() {
+			u, err := giturl.Parse("https://GitHub.com/fabric8-launcher/launcher-backend.git/")
+			Expect(err).Should(BeNil())
+			Expect(u.Host).Should(Equal("github.com"), "host should be lower case")
+			Expect(u.Repository).Should(Equal("launcher-backend"), "repository should be 'launcher-backend'")
+			Expect(u.Ref).Should(BeEmpty(), "ref should be empty")
+		})
+
+		It("No scheme", func() {
+			u, err := giturl.Parse("github.com/fabric8-launcher/launcher-backend")
+			Expect(err).Should(BeNil())
+			Expect(u.Scheme).Should(Equal("https"), "scheme should be 'https'")
+			Expect(u.Repository).Should(Equal("launcher-backend"), "repository should be 'launcher-backend'")
+		})
+	})
+
+	Context("Parse - refs", func() {
+		It("Tree", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/master/")
+			Expect(err).Should(BeNil())
+			Expect(u.Ref).Should(Equal("master"), "ref should be 'master'")
+			Expect(u.RefType).Should(Equal(giturl.Branch), "ref should be a branch")
+			Expect(u.Subpath).Should(BeEmpty(), "subpath should be empty")
+		})
+
+		It("Blob", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/blob/master/web/pom.xml")
+			Expect(err).Should(BeNil())
+			Expect(u.Ref).Should(Equal("master"), "ref should be 'master'")
+			Expect(u.Subpath).Should(Equal("web/pom.xml"), "subpath should be 'web/pom.xml'")
+		})
+
+		It("Branch containing slashes", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/feature/detect/web")
+			Expect(err).Should(BeNil())
+
+			splits := u.Splits()
+			Expect(splits).Should(HaveLen(3), "every split should be returned")
+			Expect(splits[0].Ref).Should(Equal("feature"), "shortest ref should be first")
+			Expect(splits[0].Subpath).Should(Equal("detect/web"), "subpath should follow the ref")
+			Expect(splits[1].Ref).Should(Equal("feature/detect"), "ref should contain slashes")
+			Expect(splits[1].Subpath).Should(Equal("web"), "subpath should follow the ref")
+			Expect(splits[2].Ref).Should(Equal("feature/detect/web"), "longest ref should be last")
+			Expect(splits[2].Subpath).Should(BeEmpty(), "subpath should be empty")
+		})
+
+		It("Commit", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/commit/a2eb145933e1044956aa96fac4945be37970ed19")
+			Expect(err).Should(BeNil())
+			Expect(u.Ref).Should(Equal("a2eb145933e1044956aa96fac4945be37970ed19"), "ref should be the commit sha")
+			Expect(u.RefType).Should(Equal(giturl.Commit), "ref should be a commit")
+			Expect(u.Splits()).Should(HaveLen(1), "commits should have a single split")
+		})
+
+		It("Release tag", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/releases/tag/v1.0.0")
+			Expect(err).Should(BeNil())
+			Expect(u.Ref).Should(Equal("v1.0.0"), "ref should be 'v1.0.0'")
+			Expect(u.RefType).Should(Equal(giturl.Tag), "ref should be a tag")
+		})
+
+		It("Pull request", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/pull/123/files")
+			Expect(err).Should(BeNil())
+			Expect(u.Ref).Should(Equal("123"), "ref should be the pull request number")
+			Expect(u.RefType).Should(Equal(giturl.PullRequest), "ref should be a pull request")
+			Expect(u.Candidates()).Should(BeEmpty(), "pull requests should have no candidate branches")
+		})
+
+		It("Merge request", func() {
+			u, err := giturl.Parse("https://gitlab.com/group/subgroup/project/-/merge_requests/7")
+			Expect(err).Should(BeNil())
+			Expect(u.Nested().Project()).Should(Equal("group/subgroup/project"), "project should include the groups")
+			Expect(u.Ref).Should(Equal("7"), "ref should be the merge request iid")
+			Expect(u.RefType).Should(Equal(giturl.PullRequest), "ref should be a pull request")
+		})
+
+		It("Gitlab nested groups", func() {
+			u, err := giturl.Parse("https://gitlab.com/group/subgroup/project/-/blob/develop/go.mod")
+			Expect(err).Should(BeNil())
+			Expect(u.Owner).Should(Equal("group/subgroup"), "owner should be 'group/subgroup'")
+			Expect(u.Repository).Should(Equal("project"), "repository should be 'project'")
+			Expect(u.Ref).Should(Equal("develop"), "ref should be 'develop'")
+			Expect(u.Subpath).Should(Equal("go.mod"), "subpath should be 'go.mod'")
+		})
+
+		It("Bitbucket server", func() {
+			u, err := giturl.Parse("https://bitbucket.example.com/projects/KEY/repos/slug/browse/web?at=refs%2Fheads%2Ffeature%2Fdetect")
+			Expect(err).Should(BeNil())
+			Expect(u.Owner).Should(Equal("KEY"), "owner should be 'KEY'")
+			Expect(u.Repository).Should(Equal("slug"), "repository should be 'slug'")
+			Expect(u.Ref).Should(Equal("feature/detect"), "ref should be 'feature/detect'")
+			Expect(u.Subpath).Should(Equal("web"), "subpath should be 'web'")
+		})
+	})
+
+	Context("Nested", func() {
+		It("Nested groups without ref", func() {
+			u, err := giturl.Parse("https://gitlab.com/group/subgroup/project")
+			Expect(err).Should(BeNil())
+			nested := u.Nested()
+			Expect(nested.Owner).Should(Equal("group/subgroup"), "owner should be 'group/subgroup'")
+			Expect(nested.Repository).Should(Equal("project"), "repository should be 'project'")
+			Expect(u.Owner).Should(Equal("group"), "parsed url should be left unchanged")
+		})
+	})
+
+	Context("Server", func() {
+		It("Bitbucket server clone url", func() {
+			u, err := giturl.Parse("https://bitbucket.example.com/scm/KEY/slug.git")
+			Expect(err).Should(BeNil())
+			server := u.Server()
+			Expect(server.Owner).Should(Equal("KEY"), "owner should be 'KEY'")
+			Expect(server.Repository).Should(Equal("slug"), "repository should be 'slug'")
+			Expect(server.BaseURL()).Should(Equal("https://bitbucket.example.com"), "base url should not include scm")
+			Expect(u.Owner).Should(Equal("scm/KEY"), "parsed url should be left unchanged")
+		})
+
+		It("Bitbucket server clone url - context path", func() {
+			u, err := giturl.Parse("https://bitbucket.example.com/bitbucket/scm/~jdoe/slug.git")
+			Expect(err).Should(BeNil())
+			server := u.Server()
+			Expect(server.Owner).Should(Equal("~jdoe"), "owner should be '~jdoe'")
+			Expect(server.Repository).Should(Equal("slug"), "repository should be 'slug'")
+			Expect(server.BaseURL()).Should(Equal("https://bitbucket.example.com/bitbucket"), "base url should include the context path")
+		})
+	})
+
+	Context("WithRef", func() {
+		It("Ref replaces the branch of the url", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/feature/detect")
+			Expect(err).Should(BeNil())
+			ref := u.WithRef("v1.0.0")
+			Expect(ref.Ref).Should(Equal("v1.0.0"), "ref should be 'v1.0.0'")
+			Expect(ref.Candidates()).Should(BeEmpty(), "refs should have no candidate branches")
+			Expect(u.WithBranch("feature/detect").Candidates()).Should(Equal([]string{"feature/detect"}), "branch should be the only candidate")
+		})
+	})
+
+	Context("WithPath", func() {
+		It("Subpath follows the branch", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/feature/detect/web")
+			Expect(err).Should(BeNil())
+			Expect(u.SubpathOf("feature")).Should(Equal("detect/web"), "subpath should follow 'feature'")
+			Expect(u.SubpathOf("feature/detect")).Should(Equal("web"), "subpath should follow 'feature/detect'")
+		})
+
+		It("Path takes precedence", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/feature/detect/web")
+			Expect(err).Should(BeNil())
+			u = u.WithPath("/services/api/")
+			Expect(u.Subpath).Should(Equal("services/api"), "subpath should be 'services/api'")
+			Expect(u.SubpathOf("feature/detect")).Should(Equal("services/api"), "path should take precedence over every split")
+			Expect(u.Candidates()).Should(HaveLen(3), "candidate branches should be kept")
+		})
+	})
+
+	Context("Parse - invalid", func() {
+		It("Empty", func() {
+			_, err := giturl.Parse("")
+			Expect(err).Should(Equal(giturl.ErrInvalidURL), "url should be invalid")
+		})
+
+		It("No host", func() {
+			_, err := giturl.Parse("fabric8-launcher/launcher-backend")
+			Expect(err).Should(Equal(giturl.ErrInvalidURL), "url should be invalid")
+		})
+
+		It("Unsupported scheme", func() {
+			_, err := giturl.Parse("ftp://github.com/fabric8-launcher/launcher-backend")
+			Expect(err).Should(Equal(giturl.ErrInvalidURL), "url should be invalid")
+		})
+
+		It("Missing repository", func() {
+			u, err := giturl.Parse("https://github.com/fabric8-launcher")
+			Expect(err).Should(BeNil())
+			Expect(u.Owner).Should(Equal("fabric8-launcher"), "owner should be 'fabric8-launcher'")
+			Expect(u.Repository).Should(BeEmpty(), "repository should be empty")
+		})
+	})
+})
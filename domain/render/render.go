@@ -0,0 +1,233 @@
+/*
+
+Package render creates the responses of the
+service from the detection results, shared by
+the controllers and the CLI.
+
+*/
+package render
+
+import (
+	"github.com/fabric8-services/build-tool-detector/app"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+// NewBuildTool creates the default view with
+// the build tool of highest precedence.
+func NewBuildTool(detection *types.Detection) *app.GoaBuildToolDetector {
+	buildTool := types.New(detection.BuildType)
+	buildTool.Overridden = optionalFlag(detection.Overridden)
+	return buildTool
+}
+
+// NewDetailed creates the detailed view listing
+// every detected build tool, along with the
+// details of the build tool of highest precedence.
+func NewDetailed(detection *types.Detection) *app.GoaBuildToolDetectorDetailed {
+	buildTools := make([]*app.GoaDetectedBuildTool, len(detection.Matches))
+	for i, match := range detection.Matches {
+		buildTools[i] = &app.GoaDetectedBuildTool{
+			BuildToolType: match.BuildType,
+			Confidence:    match.Confidence,
+			Evidence:      match.Evidence,
+			Runtime:       newRuntime(match.Runtime),
+			Framework:     newFramework(match.Framework),
+			Maven:         newMaven(match.Maven),
+			Gradle:        newGradle(match.Gradle),
+			Nodejs:        newNodeJS(match.NodeJS),
+			Python:        newPython(match.Python),
+			Golang:        newGolang(match.Golang),
+		}
+	}
+	detailed := &app.GoaBuildToolDetectorDetailed{
+		BuildToolType: types.New(detection.BuildType).BuildToolType,
+		Branch:        optional(detection.Branch),
+		Ref:           optional(detection.Ref),
+		Commit:        optional(detection.Commit),
+		Path:          optional(detection.Path),
+		Overridden:    optionalFlag(detection.Overridden),
+		BuilderImage:  optional(detection.BuilderImage),
+		BuildTools:    buildTools,
+	}
+	if top := detection.Top(); top != nil {
+		detailed.Runtime = newRuntime(top.Runtime)
+		detailed.Framework = newFramework(top.Framework)
+		detailed.Maven = newMaven(top.Maven)
+		detailed.Gradle = newGradle(top.Gradle)
+		detailed.Nodejs = newNodeJS(top.NodeJS)
+		detailed.Python = newPython(top.Python)
+		detailed.Golang = newGolang(top.Golang)
+	}
+	return detailed
+}
+
+// newRuntime creates the runtime details.
+func newRuntime(runtime *types.Runtime) *app.Runtime {
+	if runtime == nil {
+		return nil
+	}
+	return &app.Runtime{
+		Name:    runtime.Name,
+		Version: optional(runtime.Version),
+	}
+}
+
+// newFramework creates the framework details.
+func newFramework(framework *types.Framework) *app.Framework {
+	if framework == nil {
+		return nil
+	}
+	return &app.Framework{
+		Name:    framework.Name,
+		Version: optional(framework.Version),
+	}
+}
+
+// newMaven creates the maven details.
+func newMaven(build *types.MavenBuild) *app.Maven {
+	if build == nil {
+		return nil
+	}
+	reactor := make([]*app.MavenModule, len(build.Reactor))
+	for i, module := range build.Reactor {
+		reactor[i] = &app.MavenModule{
+			Path:       module.Path,
+			GroupID:    optional(module.GroupID),
+			ArtifactID: module.ArtifactID,
+			Version:    optional(module.Version),
+			Packaging:  module.Packaging,
+			Deployable: module.Deployable,
+			Modules:    module.Modules,
+		}
+	}
+	return &app.Maven{
+		GroupID:    optional(build.GroupID),
+		ArtifactID: build.ArtifactID,
+		Version:    optional(build.Version),
+		Packaging:  build.Packaging,
+		Deployable: build.Deployable,
+		Modules:    build.Modules,
+		Reactor:    reactor,
+	}
+}
+
+// newGradle creates the gradle details.
+func newGradle(build *types.GradleBuild) *app.Gradle {
+	if build == nil {
+		return nil
+	}
+	return &app.Gradle{
+		Dsl:     build.DSL,
+		Wrapper: build.Wrapper,
+	}
+}
+
+// newNodeJS creates the node details.
+func newNodeJS(build *types.NodeJSBuild) *app.NodeJS {
+	if build == nil {
+		return nil
+	}
+	return &app.NodeJS{
+		PackageManager: build.PackageManager,
+		NodeVersion:    optional(build.NodeVersion),
+		Scripts:        build.Scripts,
+		Typescript:     build.TypeScript,
+		Frameworks:     build.Frameworks,
+		Workspaces:     newNodeWorkspaces(build.Workspaces),
+	}
+}
+
+// newNodeWorkspaces creates the workspace details.
+func newNodeWorkspaces(workspaces *types.NodeWorkspaces) *app.NodeWorkspaces {
+	if workspaces == nil {
+		return nil
+	}
+	return &app.NodeWorkspaces{
+		Tool:     workspaces.Tool,
+		Packages: workspaces.Packages,
+	}
+}
+
+// newPython creates the python details.
+func newPython(build *types.PythonBuild) *app.Python {
+	if build == nil {
+		return nil
+	}
+	return &app.Python{
+		PackagingTool: build.PackagingTool,
+		BuildBackend:  optional(build.BuildBackend),
+	}
+}
+
+// newGolang creates the go details.
+func newGolang(build *types.GolangBuild) *app.Golang {
+	if build == nil {
+		return nil
+	}
+	return &app.Golang{
+		Module:            optional(build.Module),
+		GoVersion:         optional(build.GoVersion),
+		DependencyManager: optional(build.DependencyManager),
+		MainPackages:      build.MainPackages,
+	}
+}
+
+// NewExplanation creates the explanation
+// of the detection, nil if not requested.
+func NewExplanation(explanation *types.Explanation) *app.Explanation {
+	if explanation == nil {
+		return nil
+	}
+	requests := make([]*app.ServiceRequest, len(explanation.Requests))
+	for i, request := range explanation.Requests {
+		requests[i] = &app.ServiceRequest{
+			Method: request.Method,
+			URL:    request.URL,
+			Status: request.Status,
+			Error:  optional(request.Error),
+		}
+	}
+	probes := make([]*app.Probe, len(explanation.Probes))
+	for i, probe := range explanation.Probes {
+		files := make([]*app.FileProbe, len(probe.Files))
+		for j, file := range probe.Files {
+			files[j] = &app.FileProbe{
+				Pattern: file.Pattern,
+				File:    optional(file.File),
+				Result:  file.Result,
+				Error:   optional(file.Error),
+			}
+		}
+		probes[i] = &app.Probe{
+			BuildToolType: probe.BuildType,
+			Precedence:    probe.Precedence,
+			Files:         files,
+			Matched:       probe.Matched,
+			Reason:        probe.Reason,
+		}
+	}
+	return &app.Explanation{
+		Requests: requests,
+		Probes:   probes,
+		Override: optional(explanation.Override),
+		Decision: explanation.Decision,
+	}
+}
+
+// optional returns a pointer to the
+// value, or nil if it is empty.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// optionalFlag returns a pointer to
+// the flag, or nil if it is not set.
+func optionalFlag(value bool) *bool {
+	if !value {
+		return nil
+	}
+	return &value
+}
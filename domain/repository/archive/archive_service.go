@@ -0,0 +1,314 @@
+/*
+
+Package archive implements a way to detect the
+build tools of an uploaded zip or tar.gz archive
+of the sources, such as a generated project
+which is not pushed to a git service yet.
+
+*/
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+const (
+	root      = "."
+	parentDir = ".."
+	slash     = "/"
+)
+
+var (
+	// ErrUnsupportedArchive the upload is
+	// neither a zip nor a tar.gz archive.
+	ErrUnsupportedArchive = errors.New("unsupported archive, expected zip or tar.gz")
+
+	// ErrArchiveTooLarge the archive, or the
+	// files it holds, exceed the size limits.
+	ErrArchiveTooLarge = errors.New("archive too large")
+
+	// ErrTooManyEntries the archive holds more
+	// files and directories than allowed.
+	ErrTooManyEntries = errors.New("archive holds too many entries")
+
+	zipMagic      = []byte("PK\x03\x04")
+	emptyZipMagic = []byte("PK\x05\x06")
+	gzipMagic     = []byte("\x1f\x8b")
+)
+
+// archiveRepository lists the files of an archive,
+// whose contents are only extracted when fetched.
+type archiveRepository struct {
+	files      map[string]int
+	listing    []types.Entry
+	open       func(index int) (io.ReadCloser, error)
+	extracted  int64
+	path       string
+	precedence []string
+}
+
+// limits are the limits an
+// archive must stay within.
+type limits struct {
+	size      int64
+	entries   int
+	extracted int64
+}
+
+// Create lists the zip or tar.gz archive read from body,
+// within the upload limits of the configuration. Only
+// the archive is held in memory, the files are extracted
+// when the detectors fetch them. The build tools are
+// detected in path, relative to the root of the archive.
+// When every entry is held by a single directory, as in
+// archives of a project directory, it is the root of
+// the archive.
+func Create(body io.Reader, path string, configuration config.Configuration) (types.RepositoryService, error) {
+	limits := limits{
+		size:      configuration.GetUploadMaxSize(),
+		entries:   configuration.GetUploadMaxEntries(),
+		extracted: configuration.GetUploadMaxExtracted(),
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(body, limits.size+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limits.size {
+		return nil, ErrArchiveTooLarge
+	}
+
+	repository := archiveRepository{
+		files:      make(map[string]int),
+		extracted:  limits.extracted,
+		path:       path,
+		precedence: configuration.GetDetectorPrecedence(),
+	}
+	switch {
+	case bytes.HasPrefix(content, zipMagic), bytes.HasPrefix(content, emptyZipMagic):
+		err = repository.listZip(content, limits)
+	case bytes.HasPrefix(content, gzipMagic):
+		err = repository.listTarGz(content, limits)
+	default:
+		err = ErrUnsupportedArchive
+	}
+	if err != nil {
+		return nil, err
+	}
+	repository.stripRoot()
+	return repository, nil
+}
+
+// DetectBuildTool returns the build tools detected
+// within the archive, pinned by its override file
+// if any. The build tool type is set to Unknown
+// in case of an error.
+func (a archiveRepository) DetectBuildTool(ctx context.Context) (*types.Detection, error) {
+	return types.DetectIn(ctx, a, a.precedence)
+}
+
+// Contents returns the files of the archive, rooted
+// at the requested path. Archives have no revision.
+func (a archiveRepository) Contents(ctx context.Context) (*types.Tree, types.Revision, error) {
+	files, err := types.NewTree(a.listing, a.fetch).Sub(a.path)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+	return files, types.Revision{}, nil
+}
+
+// fetch extracts the file from the archive.
+func (a archiveRepository) fetch(ctx context.Context, file string) ([]byte, error) {
+	index, ok := a.files[file]
+	if !ok {
+		return nil, types.ErrFileNotFound
+	}
+	entry, err := a.open(index)
+	if err != nil {
+		return nil, ErrUnsupportedArchive
+	}
+	defer entry.Close()
+
+	content, err := ioutil.ReadAll(io.LimitReader(entry, a.extracted+1))
+	if err != nil {
+		return nil, ErrUnsupportedArchive
+	}
+	if int64(len(content)) > a.extracted {
+		return nil, ErrArchiveTooLarge
+	}
+	return content, nil
+}
+
+// Owner returns no owner, archives have none.
+func (a archiveRepository) Owner() string {
+	return ""
+}
+
+// Repository returns no repository,
+// archives have none.
+func (a archiveRepository) Repository() string {
+	return ""
+}
+
+// Branch returns no branch,
+// archives have none.
+func (a archiveRepository) Branch() string {
+	return ""
+}
+
+// listZip lists the entries of the zip archive, the
+// files are opened from the central directory.
+func (a *archiveRepository) listZip(content []byte, limits limits) error {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return ErrUnsupportedArchive
+	}
+	if len(reader.File) > limits.entries {
+		return ErrTooManyEntries
+	}
+
+	for i, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			a.add(file.Name, true)
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		if err := a.addFile(file.Name, i, int64(file.UncompressedSize64), &limits); err != nil {
+			return err
+		}
+	}
+	a.open = func(index int) (io.ReadCloser, error) {
+		return reader.File[index].Open()
+	}
+	return nil
+}
+
+// listTarGz lists the entries of the tar.gz archive,
+// the files are read by decompressing the archive
+// up to their entry.
+func (a *archiveRepository) listTarGz(content []byte, limits limits) error {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return ErrUnsupportedArchive
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for i := 0; ; i++ {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ErrUnsupportedArchive
+		}
+		if i >= limits.entries {
+			return ErrTooManyEntries
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			a.add(header.Name, true)
+		case tar.TypeReg, tar.TypeRegA:
+			if err := a.addFile(header.Name, i, header.Size, &limits); err != nil {
+				return err
+			}
+		}
+	}
+	a.open = func(index int) (io.ReadCloser, error) {
+		return openTarGz(content, index)
+	}
+	return nil
+}
+
+// openTarGz returns the reader of
+// the entry of the tar.gz archive.
+func openTarGz(content []byte, index int) (io.ReadCloser, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	reader := tar.NewReader(gz)
+	for i := 0; i <= index; i++ {
+		if _, err := reader.Next(); err != nil {
+			gz.Close()
+			return nil, err
+		}
+	}
+	return tarEntry{Reader: reader, Closer: gz}, nil
+}
+
+// tarEntry reads an entry of a tar.gz
+// archive, closing the decompression.
+type tarEntry struct {
+	io.Reader
+	io.Closer
+}
+
+// addFile lists the file at the index of the archive,
+// within the limit of the size left to extract.
+func (a *archiveRepository) addFile(name string, index int, size int64, limits *limits) error {
+	if size < 0 || size > limits.extracted {
+		return ErrArchiveTooLarge
+	}
+	limits.extracted -= size
+	if file, ok := a.add(name, false); ok {
+		a.files[file] = index
+	}
+	return nil
+}
+
+// add lists the entry, returning its cleaned path.
+// Entries outside of the archive root are ignored.
+func (a *archiveRepository) add(name string, dir bool) (string, bool) {
+	file := path.Clean(strings.TrimLeft(name, slash))
+	if file == root || file == parentDir || strings.HasPrefix(file, parentDir+slash) {
+		return "", false
+	}
+	a.listing = append(a.listing, types.Entry{Path: file, Dir: dir})
+	return file, true
+}
+
+// stripRoot makes the directory holding every
+// entry of the archive, if any, its root.
+func (a *archiveRepository) stripRoot() {
+	var top string
+	for _, entry := range a.listing {
+		dir := strings.SplitN(entry.Path, slash, 2)[0]
+		if top == "" {
+			top = dir
+		}
+		if dir != top || (dir == entry.Path && !entry.Dir) {
+			return
+		}
+	}
+	if top == "" {
+		return
+	}
+
+	prefix := top + slash
+	var listing []types.Entry
+	for _, entry := range a.listing {
+		if entry.Path != top {
+			listing = append(listing, types.Entry{Path: strings.TrimPrefix(entry.Path, prefix), Dir: entry.Dir})
+		}
+	}
+	files := make(map[string]int, len(a.files))
+	for file, index := range a.files {
+		files[strings.TrimPrefix(file, prefix)] = index
+	}
+	a.listing, a.files = listing, files
+}
@@ -0,0 +1,173 @@
+/*
+
+Package archive_test is used to test the functionality
+within the archive package.
+
+*/
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/archive"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// file is an entry of the archives
+// created by the tests, in order.
+type file struct {
+	name    string
+	content string
+}
+
+// newZip creates a zip archive of the files,
+// names ending with a slash are directories.
+func newZip(files ...file) *bytes.Buffer {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, f := range files {
+		entry, err := writer.Create(f.name)
+		Expect(err).Should(BeNil())
+		_, err = entry.Write([]byte(f.content))
+		Expect(err).Should(BeNil())
+	}
+	Expect(writer.Close()).Should(BeNil())
+	return &buffer
+}
+
+// newTarGz creates a tar.gz archive of the files,
+// names ending with a slash are directories.
+func newTarGz(files ...file) *bytes.Buffer {
+	var buffer bytes.Buffer
+	gz := gzip.NewWriter(&buffer)
+	writer := tar.NewWriter(gz)
+	for _, f := range files {
+		header := tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(f.name, "/") {
+			header.Typeflag, header.Mode = tar.TypeDir, 0755
+		}
+		Expect(writer.WriteHeader(&header)).Should(BeNil())
+		_, err := writer.Write([]byte(f.content))
+		Expect(err).Should(BeNil())
+	}
+	Expect(writer.Close()).Should(BeNil())
+	Expect(gz.Close()).Should(BeNil())
+	return &buffer
+}
+
+var _ = Describe("ArchiveService", func() {
+	ctx := context.TODO()
+
+	AfterEach(func() {
+		os.Unsetenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_SIZE")
+		os.Unsetenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_ENTRIES")
+		os.Unsetenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_EXTRACTED")
+	})
+
+	It("Zip of a project directory", func() {
+		body := newZip(
+			file{name: "booster/"},
+			file{name: "booster/pom.xml", content: "<project><artifactId>booster</artifactId></project>"},
+			file{name: "booster/src/main/java/App.java", content: "class App {}"},
+		)
+		repositoryService, err := archive.Create(body, "", *config.New())
+		Expect(err).Should(BeNil())
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.BuildType).Should(Equal(types.Maven), "build tool type should be maven")
+		Expect(detection.Top().Maven.ArtifactID).Should(Equal("booster"), "pom.xml should be extracted")
+	})
+
+	It("Tar.gz and path", func() {
+		body := newTarGz(
+			file{name: "pom.xml", content: "<project><artifactId>app</artifactId></project>"},
+			file{name: "frontend/"},
+			file{name: "frontend/package.json", content: `{"packageManager": "pnpm@8.6.0"}`},
+			file{name: "../package.json", content: "{}"},
+		)
+		repositoryService, err := archive.Create(body, "frontend", *config.New())
+		Expect(err).Should(BeNil())
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.BuildType).Should(Equal(types.NodeJS), "build tool type should be nodejs")
+		Expect(detection.Path).Should(Equal("frontend"), "path should be detected")
+		Expect(detection.Top().NodeJS.PackageManager).Should(Equal(types.PNPM), "package.json should be extracted")
+	})
+
+	It("Files extracted when read", func() {
+		body := newTarGz(
+			file{name: "pom.xml", content: "<project><artifactId>first</artifactId></project>"},
+			file{name: "README.md", content: "# app"},
+			file{name: "pom.xml", content: "<project><artifactId>app</artifactId></project>"},
+		)
+		repositoryService, err := archive.Create(body, "", *config.New())
+		Expect(err).Should(BeNil())
+
+		files, _, err := repositoryService.Contents(ctx)
+		Expect(err).Should(BeNil())
+		content, err := files.ReadFile(ctx, "README.md")
+		Expect(err).Should(BeNil())
+		Expect(string(content)).Should(Equal("# app"), "file should be extracted")
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.Top().Maven.ArtifactID).Should(Equal("app"), "the last entry of a file should be extracted")
+	})
+
+	It("No marker files - unknown", func() {
+		repositoryService, err := archive.Create(newTarGz(file{name: "README.md", content: "# app"}), "", *config.New())
+		Expect(err).Should(BeNil())
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(Equal(types.ErrFailedContentRetrieval))
+		Expect(detection.BuildType).Should(Equal(types.Unknown), "build tool type should be unknown")
+	})
+
+	It("Path not found", func() {
+		repositoryService, err := archive.Create(newZip(file{name: "pom.xml", content: "<project/>"}), "backend", *config.New())
+		Expect(err).Should(BeNil())
+
+		_, err = repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(Equal(types.ErrResourceNotFound))
+	})
+
+	It("Unsupported archive", func() {
+		_, err := archive.Create(strings.NewReader("<project/>"), "", *config.New())
+		Expect(err).Should(Equal(archive.ErrUnsupportedArchive))
+	})
+
+	It("Archive too large", func() {
+		os.Setenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_SIZE", "64")
+		_, err := archive.Create(newZip(file{name: "pom.xml", content: "<project/>"}), "", *config.New())
+		Expect(err).Should(Equal(archive.ErrArchiveTooLarge))
+	})
+
+	It("Files extracted too large", func() {
+		os.Setenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_EXTRACTED", "1024")
+		body := newTarGz(file{name: "pom.xml", content: strings.Repeat(" ", 2048)})
+		_, err := archive.Create(body, "", *config.New())
+		Expect(err).Should(Equal(archive.ErrArchiveTooLarge))
+	})
+
+	It("Too many entries", func() {
+		os.Setenv("BUILD_TOOL_DETECTOR_UPLOAD_MAX_ENTRIES", "2")
+		body := newZip(file{name: "pom.xml"}, file{name: "package.json"}, file{name: "go.mod"})
+		_, err := archive.Create(body, "", *config.New())
+		Expect(err).Should(Equal(archive.ErrTooManyEntries))
+
+		body = newTarGz(file{name: "pom.xml"}, file{name: "package.json"}, file{name: "go.mod"})
+		_, err = archive.Create(body, "", *config.New())
+		Expect(err).Should(Equal(archive.ErrTooManyEntries))
+	})
+})
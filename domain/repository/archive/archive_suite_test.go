@@ -0,0 +1,19 @@
+/*
+
+Package archive_test is used to test the functionality
+within the archive package.
+
+*/
+package archive_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestArchive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Archive Suite")
+}
@@ -0,0 +1,282 @@
+package bitbucket
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	"github.com/fabric8-services/build-tool-detector/log"
+)
+
+const (
+	src           = "src"
+	fileType      = "commit_file"
+	directoryType = "commit_directory"
+	cloudAPIPath  = "/2.0/repositories/"
+	cloudBranches = "/refs/branches/"
+	cloudCommit   = "/commit/"
+	cloudPulls    = "/pullrequests/"
+	cloudPullRef  = "pull-requests/"
+	cloudPageLen  = "100"
+	cloudMaxDepth = "10"
+)
+
+// cloudRepository contains
+// values pertaining to a bitbucket
+// cloud repository.
+type cloudRepository struct {
+	apiURL     string
+	workspace  string
+	repository string
+	branch     string
+	candidates []string
+	ref        string
+	refType    string
+	location   *giturl.URL
+	precedence []string
+	client     apiClient
+}
+
+// cloudBranch is the subset of the bitbucket
+// cloud branch resource used for detection.
+type cloudBranch struct {
+	Name   string `json:"name"`
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+// cloudRepositoryResource is the subset of the
+// bitbucket cloud repository resource used
+// for detection.
+type cloudRepositoryResource struct {
+	MainBranch struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+// cloudCommitResource is the subset of the
+// bitbucket cloud commit resource used
+// for detection.
+type cloudCommitResource struct {
+	Hash string `json:"hash"`
+}
+
+// cloudPullRequest is the subset of the
+// bitbucket cloud pull request resource
+// used for detection.
+type cloudPullRequest struct {
+	Source struct {
+		Commit cloudCommitResource `json:"commit"`
+	} `json:"source"`
+}
+
+// cloudListing is a page of the
+// bitbucket cloud source listing.
+type cloudListing struct {
+	Values []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	} `json:"values"`
+	Next string `json:"next"`
+}
+
+// CreateCloud instantiate Bitbucket Cloud repository,
+// the url is expected as in
+// https://bitbucket.org/workspace/repository/src/branch.
+func CreateCloud(location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
+	var repositoryService types.RepositoryService
+
+	if location == nil || location.Owner == "" || location.Repository == "" {
+		return repositoryService, types.ErrInvalidPath
+	}
+
+	// The main branch of the repository is resolved
+	// if the url does not point at a ref. Branches
+	// within the url may contain slashes, every
+	// split of the url is a candidate.
+	candidates := location.Candidates()
+	var branch string
+	if len(candidates) > 0 {
+		branch = candidates[0]
+	}
+
+	repositoryService = cloudRepository{
+		apiURL:     strings.TrimSuffix(configuration.GetBitbucketAPIURL(), slash),
+		workspace:  location.Owner,
+		repository: location.Repository,
+		branch:     branch,
+		candidates: candidates,
+		ref:        location.Ref,
+		refType:    location.RefType,
+		location:   location,
+		precedence: configuration.GetDetectorPrecedence(),
+		client:     newAPIClient(token),
+	}
+	return repositoryService, nil
+}
+
+// DetectBuildTool gets the contents for the service and returns
+// the detected build tools, pinned by the override file of the
+// repository if any. The build tool type is set to Unknown
+// in case of an error.
+func (c cloudRepository) DetectBuildTool(ctx context.Context) (*types.Detection, error) {
+	return types.DetectIn(ctx, c, c.precedence)
+}
+
+// Contents returns the files of the repository
+// and the revision resolved.
+func (c cloudRepository) Contents(ctx context.Context) (*types.Tree, types.Revision, error) {
+	revision, err := c.getRevision(ctx)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err := c.getContents(ctx, revision.Commit)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err = files.Sub(c.location.SubpathOf(revision.Branch))
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	return files, revision, nil
+}
+
+// Owner returns the workspace of a repository.
+func (c cloudRepository) Owner() string {
+	return c.workspace
+}
+
+// Repository returns the name of a repository.
+func (c cloudRepository) Repository() string {
+	return c.repository
+}
+
+// Branch returns the branch of a repository, empty
+// if the main branch is to be resolved or if the
+// repository points at another ref.
+func (c cloudRepository) Branch() string {
+	return c.branch
+}
+
+// getContents fetches the listing of all files of the
+// branch, the detectors are evaluated against the
+// listing. File contents are only fetched on demand.
+func (c cloudRepository) getContents(ctx context.Context, hash string) (*types.Tree, error) {
+	entries, truncated, err := c.list(ctx, hash, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Past the page limit, the listing may miss the
+	// marker files at the root of the repository,
+	// which are therefore listed separately.
+	if truncated {
+		log.Logger().WithField(repositoryField, c.repository).Warnf(types.ErrTruncatedTree.Error())
+		rootEntries, _, err := c.list(ctx, hash, false)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, rootEntries...)
+	}
+
+	return types.NewTree(entries, func(ctx context.Context, path string) ([]byte, error) {
+		content, err := c.client.raw(ctx, c.endpoint()+slash+src+slash+hash+slash+escapePath(path))
+		if err != nil {
+			return nil, types.ErrFailedContentRetrieval
+		}
+		return content, nil
+	}), nil
+}
+
+// list makes paginated requests to list the files
+// of the commit, or of its root unless recursive.
+// Whether the listing was truncated at the page
+// limit is returned along with it.
+func (c cloudRepository) list(ctx context.Context, hash string, recursive bool) ([]types.Entry, bool, error) {
+	query := url.Values{"pagelen": {cloudPageLen}}
+	if recursive {
+		query.Set("max_depth", cloudMaxDepth)
+	}
+	next := c.endpoint() + slash + src + slash + hash + slash + "?" + query.Encode()
+
+	var entries []types.Entry
+	for i := 0; i < maxPages && next != ""; i++ {
+		var listing cloudListing
+		if err := c.client.get(ctx, next, &listing); err != nil {
+			return nil, false, types.ErrFailedContentRetrieval
+		}
+		for _, value := range listing.Values {
+			switch value.Type {
+			case fileType:
+				entries = append(entries, types.Entry{Path: value.Path})
+			case directoryType:
+				entries = append(entries, types.Entry{Path: value.Path, Dir: true})
+			}
+		}
+		next = listing.Next
+	}
+	return entries, next != "", nil
+}
+
+// getRevision resolves the commit the build tools
+// are detected at, from the branch, the ref or the
+// pull request the repository points at.
+func (c cloudRepository) getRevision(ctx context.Context) (types.Revision, error) {
+	switch {
+	case c.refType == giturl.PullRequest:
+		var pullRequest cloudPullRequest
+		if err := c.client.get(ctx, c.endpoint()+cloudPulls+url.PathEscape(c.ref), &pullRequest); err != nil || pullRequest.Source.Commit.Hash == "" {
+			return types.Revision{}, types.ErrResourceNotFound
+		}
+		return types.Revision{Ref: cloudPullRef + c.ref, Commit: pullRequest.Source.Commit.Hash}, nil
+	case c.refType != giturl.Branch && c.ref != "":
+		return c.getRef(ctx)
+	}
+
+	candidates := c.candidates
+	if len(candidates) == 0 {
+		var repository cloudRepositoryResource
+		if err := c.client.get(ctx, c.endpoint(), &repository); err != nil || repository.MainBranch.Name == "" {
+			return types.Revision{}, types.ErrResourceNotFound
+		}
+		candidates = []string{repository.MainBranch.Name}
+	}
+
+	// The candidate branches are requested in order.
+	for _, candidate := range candidates {
+		var branch cloudBranch
+		if err := c.client.get(ctx, c.endpoint()+cloudBranches+url.PathEscape(candidate), &branch); err != nil {
+			continue
+		}
+		return types.Revision{Branch: candidate, Commit: branch.Target.Hash}, nil
+	}
+	// Urls as in /src/<ref>/ may point
+	// at a tag or a commit as well.
+	if len(c.candidates) > 0 {
+		return c.getRef(ctx)
+	}
+	return types.Revision{}, types.ErrResourceNotFound
+}
+
+// getRef resolves the commit of
+// the ref, a tag or a commit hash.
+func (c cloudRepository) getRef(ctx context.Context) (types.Revision, error) {
+	var commit cloudCommitResource
+	if err := c.client.get(ctx, c.endpoint()+cloudCommit+url.PathEscape(c.ref), &commit); err != nil || commit.Hash == "" {
+		return types.Revision{}, types.ErrResourceNotFound
+	}
+	return types.Revision{Ref: c.ref, Commit: commit.Hash}, nil
+}
+
+// endpoint returns the api
+// url of the repository.
+func (c cloudRepository) endpoint() string {
+	return c.apiURL + cloudAPIPath + url.PathEscape(c.workspace) + slash + url.PathEscape(c.repository)
+}
@@ -0,0 +1,172 @@
+/*
+
+Package bitbucket_test is used to test the functionality
+within the bitbucket package.
+
+*/
+package bitbucket_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/bitbucket"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BitbucketCloud", func() {
+
+	Context("CreateCloud", func() {
+		It("Default branch", func() {
+			location, _ := giturl.Parse("https://bitbucket.org/workspace/repository")
+			repositoryService, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Owner()).Should(Equal("workspace"), "owner should be 'workspace'")
+			Expect(repositoryService.Repository()).Should(Equal("repository"), "repository should be 'repository'")
+			Expect(repositoryService.Branch()).Should(BeEmpty(), "main branch should be resolved")
+		})
+
+		It("Branch in url", func() {
+			location, _ := giturl.Parse("https://bitbucket.org/workspace/repository/src/develop/")
+			repositoryService, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Branch()).Should(Equal("develop"), "branch should be 'develop'")
+		})
+
+		It("Missing repository", func() {
+			location, _ := giturl.Parse("https://bitbucket.org/workspace")
+			_, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(Equal(types.ErrInvalidPath), "path should be invalid")
+		})
+	})
+
+	Context("DetectBuildTool", func() {
+		var server *httptest.Server
+		ctx := context.TODO()
+		location, _ := giturl.Parse("https://bitbucket.org/workspace/repository/src/develop")
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/2.0/repositories/workspace/repository", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"full_name": "workspace/repository", "mainbranch": {"name": "develop", "type": "branch"}}`)
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/refs/branches/develop", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, `{"name": "develop", "target": {"hash": "7f5d3c1"}}`)
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/src/7f5d3c1/", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") == "2" {
+					fmt.Fprint(w, `{"values": [{"path": "pom.xml", "type": "commit_file"}]}`)
+					return
+				}
+				fmt.Fprintf(w, `{"values": [{"path": "src", "type": "commit_directory"}, {"path": "go.mod", "type": "commit_file"}], "next": "%s/2.0/repositories/workspace/repository/src/7f5d3c1/?page=2"}`, server.URL)
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/src/7f5d3c1/go.mod", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "module bitbucket.org/workspace/repository\n")
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/commit/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"hash": "7f5d3c1"}`)
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/commit/v2.0.0", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"hash": "9e8d7c6"}`)
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/src/9e8d7c6/", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("max_depth") == "" {
+					fmt.Fprint(w, `{"values": [{"path": "src", "type": "commit_directory"}, {"path": "pom.xml", "type": "commit_file"}]}`)
+					return
+				}
+				fmt.Fprintf(w, `{"values": [{"path": "src/App.java", "type": "commit_file"}], "next": "%s/2.0/repositories/workspace/repository/src/9e8d7c6/?max_depth=10"}`, server.URL)
+			})
+			mux.HandleFunc("/2.0/repositories/workspace/repository/pullrequests/12", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": 12, "source": {"branch": {"name": "feature"}, "commit": {"hash": "7f5d3c1"}}}`)
+			})
+			server = httptest.NewServer(mux)
+			os.Setenv("BUILD_TOOL_DETECTOR_BITBUCKET_API_URL", server.URL)
+		})
+		AfterEach(func() {
+			os.Unsetenv("BUILD_TOOL_DETECTOR_BITBUCKET_API_URL")
+			server.Close()
+		})
+
+		It("Paginated listing", func() {
+			repositoryService, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "build tool type should be 'maven'")
+			Expect(detection.Matches).Should(HaveLen(2), "maven and golang should be detected")
+			Expect(detection.Matches[1].Golang.Module).Should(Equal("bitbucket.org/workspace/repository"), "module should be read from go.mod")
+		})
+
+		It("Main branch", func() {
+			location, _ := giturl.Parse("https://bitbucket.org/workspace/repository")
+			repositoryService, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Branch).Should(Equal("develop"), "main branch should be analyzed")
+		})
+
+		It("Release tag", func() {
+			repositoryService, err := bitbucket.CreateCloud(location.WithRef("v1.0.0"), *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("v1.0.0"), "ref should be 'v1.0.0'")
+			Expect(detection.Commit).Should(Equal("7f5d3c1"), "commit of the tag should be analyzed")
+			Expect(detection.Branch).Should(BeEmpty(), "no branch should be analyzed")
+		})
+
+		It("Tag in url", func() {
+			location, _ := giturl.Parse("https://bitbucket.org/workspace/repository/src/v1.0.0/")
+			repositoryService, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("v1.0.0"), "tag should be resolved once no branch matched")
+			Expect(detection.Commit).Should(Equal("7f5d3c1"), "commit of the tag should be analyzed")
+		})
+
+		It("Truncated listing", func() {
+			repositoryService, err := bitbucket.CreateCloud(location.WithRef("v2.0.0"), *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "root should be listed separately")
+		})
+
+		It("Pull request", func() {
+			location, _ := giturl.Parse("https://bitbucket.org/workspace/repository/pull-requests/12")
+			repositoryService, err := bitbucket.CreateCloud(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("pull-requests/12"), "ref should be the pull request")
+			Expect(detection.Commit).Should(Equal("7f5d3c1"), "source commit of the pull request should be analyzed")
+		})
+
+		It("Branch not found", func() {
+			repositoryService, err := bitbucket.CreateCloud(location.WithBranch("missing"), *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(types.ErrResourceNotFound), "branch should not be found")
+		})
+	})
+})
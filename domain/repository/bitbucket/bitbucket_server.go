@@ -0,0 +1,321 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	"github.com/fabric8-services/build-tool-detector/log"
+)
+
+const (
+	projects       = "projects"
+	repos          = "repos"
+	at             = "at"
+	serverAPIPath  = "/rest/api/1.0/"
+	serverBranches = "/branches"
+	serverDefault  = "/branches/default"
+	serverCommits  = "/commits/"
+	serverPulls    = "/pull-requests/"
+	serverPullRef  = "refs/pull-requests/%s/from"
+	serverFiles    = "/files"
+	serverBrowse   = "/browse"
+	serverDirType  = "DIRECTORY"
+	serverRaw      = "/raw/"
+	serverLimit    = "1000"
+)
+
+// serverRepository contains
+// values pertaining to a bitbucket
+// server repository.
+type serverRepository struct {
+	baseURL    string
+	project    string
+	repository string
+	branch     string
+	candidates []string
+	ref        string
+	refType    string
+	location   *giturl.URL
+	precedence []string
+	client     apiClient
+}
+
+// serverBranchList is the subset of the bitbucket
+// server branch listing used for detection.
+type serverBranchList struct {
+	Values []struct {
+		DisplayID    string `json:"displayId"`
+		LatestCommit string `json:"latestCommit"`
+	} `json:"values"`
+}
+
+// serverBranch is the subset of the bitbucket
+// server branch resource used for detection.
+type serverBranch struct {
+	DisplayID string `json:"displayId"`
+}
+
+// serverCommit is the subset of the bitbucket
+// server commit resource used for detection.
+type serverCommit struct {
+	ID string `json:"id"`
+}
+
+// serverPullRequest is the subset of the bitbucket
+// server pull request resource used for detection.
+type serverPullRequest struct {
+	FromRef struct {
+		LatestCommit string `json:"latestCommit"`
+	} `json:"fromRef"`
+}
+
+// serverFileList is a page of the
+// bitbucket server file listing.
+type serverFileList struct {
+	Values        []string `json:"values"`
+	IsLastPage    bool     `json:"isLastPage"`
+	NextPageStart int      `json:"nextPageStart"`
+}
+
+// serverDirectory is a page of the bitbucket
+// server listing of the root directory.
+type serverDirectory struct {
+	Children struct {
+		Values []struct {
+			Path struct {
+				ToString string `json:"toString"`
+			} `json:"path"`
+			Type string `json:"type"`
+		} `json:"values"`
+		IsLastPage    bool `json:"isLastPage"`
+		NextPageStart int  `json:"nextPageStart"`
+	} `json:"children"`
+}
+
+// CreateServer instantiate Bitbucket Server repository,
+// the url is expected as in
+// https://host/projects/KEY/repos/slug/browse?at=refs/heads/branch,
+// personal repositories as in https://host/users/user/repos/slug,
+// or as the clone url https://host/scm/KEY/slug.git parsed by
+// giturl.URL.Server.
+func CreateServer(location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
+	var repositoryService types.RepositoryService
+
+	if location == nil || location.Owner == "" || location.Repository == "" {
+		return repositoryService, types.ErrInvalidPath
+	}
+
+	// The default branch of the repository is resolved
+	// if the url does not point at a ref.
+	candidates := location.Candidates()
+	var branch string
+	if len(candidates) > 0 {
+		branch = candidates[0]
+	}
+
+	repositoryService = serverRepository{
+		baseURL:    location.BaseURL(),
+		project:    location.Owner,
+		repository: location.Repository,
+		branch:     branch,
+		candidates: candidates,
+		ref:        location.Ref,
+		refType:    location.RefType,
+		location:   location,
+		precedence: configuration.GetDetectorPrecedence(),
+		client:     newAPIClient(token),
+	}
+	return repositoryService, nil
+}
+
+// DetectBuildTool gets the contents for the service and returns
+// the detected build tools, pinned by the override file of the
+// repository if any. The build tool type is set to Unknown
+// in case of an error.
+func (s serverRepository) DetectBuildTool(ctx context.Context) (*types.Detection, error) {
+	return types.DetectIn(ctx, s, s.precedence)
+}
+
+// Contents returns the files of the repository
+// and the revision resolved.
+func (s serverRepository) Contents(ctx context.Context) (*types.Tree, types.Revision, error) {
+	revision, err := s.getRevision(ctx)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err := s.getContents(ctx, revision.Commit)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err = files.Sub(s.location.SubpathOf(revision.Branch))
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	return files, revision, nil
+}
+
+// Owner returns the project key of a repository,
+// personal projects are prefixed with '~'.
+func (s serverRepository) Owner() string {
+	return s.project
+}
+
+// Repository returns the slug of a repository.
+func (s serverRepository) Repository() string {
+	return s.repository
+}
+
+// Branch returns the branch of a repository, empty
+// if the default branch is to be resolved or if
+// the repository points at another ref.
+func (s serverRepository) Branch() string {
+	return s.branch
+}
+
+// getContents fetches the listing of all files of the
+// branch, the detectors are evaluated against the
+// listing. File contents are only fetched on demand.
+func (s serverRepository) getContents(ctx context.Context, commit string) (*types.Tree, error) {
+	var entries []types.Entry
+	start, last := 0, false
+	for i := 0; i < maxPages && !last; i++ {
+		var files serverFileList
+		if err := s.client.get(ctx, s.endpoint()+serverFiles+"?"+pageQuery(commit, start), &files); err != nil {
+			return nil, types.ErrFailedContentRetrieval
+		}
+		for _, file := range files.Values {
+			entries = append(entries, types.Entry{Path: file})
+		}
+		start, last = files.NextPageStart, files.IsLastPage
+	}
+
+	// Past the page limit, the listing may miss the
+	// marker files at the root of the repository,
+	// which are therefore listed separately.
+	if !last {
+		log.Logger().WithField(repositoryField, s.repository).Warnf(types.ErrTruncatedTree.Error())
+		rootEntries, err := s.listRoot(ctx, commit)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, rootEntries...)
+	}
+
+	return types.NewTree(entries, func(ctx context.Context, path string) ([]byte, error) {
+		query := url.Values{at: {commit}}
+		content, err := s.client.raw(ctx, s.endpoint()+serverRaw+escapePath(path)+"?"+query.Encode())
+		if err != nil {
+			return nil, types.ErrFailedContentRetrieval
+		}
+		return content, nil
+	}), nil
+}
+
+// listRoot makes paginated requests to list
+// the root directory of the commit.
+func (s serverRepository) listRoot(ctx context.Context, commit string) ([]types.Entry, error) {
+	var entries []types.Entry
+	start, last := 0, false
+	for i := 0; i < maxPages && !last; i++ {
+		var dir serverDirectory
+		if err := s.client.get(ctx, s.endpoint()+serverBrowse+"?"+pageQuery(commit, start), &dir); err != nil {
+			return nil, types.ErrFailedContentRetrieval
+		}
+		for _, child := range dir.Children.Values {
+			entries = append(entries, types.Entry{Path: child.Path.ToString, Dir: child.Type == serverDirType})
+		}
+		start, last = dir.Children.NextPageStart, dir.Children.IsLastPage
+	}
+	return entries, nil
+}
+
+// pageQuery returns the query of the
+// page of a listing at the commit.
+func pageQuery(commit string, start int) string {
+	query := url.Values{
+		at:      {commit},
+		"limit": {serverLimit},
+		"start": {strconv.Itoa(start)},
+	}
+	return query.Encode()
+}
+
+// getRevision resolves the commit the build tools
+// are detected at, from the branch, the ref or the
+// pull request the repository points at.
+func (s serverRepository) getRevision(ctx context.Context) (types.Revision, error) {
+	switch {
+	case s.refType == giturl.PullRequest:
+		var pullRequest serverPullRequest
+		if err := s.client.get(ctx, s.endpoint()+serverPulls+url.PathEscape(s.ref), &pullRequest); err != nil || pullRequest.FromRef.LatestCommit == "" {
+			return types.Revision{}, types.ErrResourceNotFound
+		}
+		return types.Revision{Ref: fmt.Sprintf(serverPullRef, s.ref), Commit: pullRequest.FromRef.LatestCommit}, nil
+	case s.refType != giturl.Branch && s.ref != "":
+		return s.getRef(ctx)
+	}
+
+	candidates := s.candidates
+	if len(candidates) == 0 {
+		var branch serverBranch
+		if err := s.client.get(ctx, s.endpoint()+serverDefault, &branch); err != nil || branch.DisplayID == "" {
+			return types.Revision{}, types.ErrResourceNotFound
+		}
+		candidates = []string{branch.DisplayID}
+	}
+
+	// The candidate branches are requested in order.
+	for _, candidate := range candidates {
+		commit, err := s.getCommit(ctx, candidate)
+		if err != nil {
+			continue
+		}
+		return types.Revision{Branch: candidate, Commit: commit}, nil
+	}
+	// The ref of the url, as in ?at=<sha>,
+	// may be a commit rather than a branch.
+	if len(s.candidates) > 0 {
+		return s.getRef(ctx)
+	}
+	return types.Revision{}, types.ErrResourceNotFound
+}
+
+// getRef resolves the commit of the
+// ref, a tag or a commit sha.
+func (s serverRepository) getRef(ctx context.Context) (types.Revision, error) {
+	var commit serverCommit
+	if err := s.client.get(ctx, s.endpoint()+serverCommits+url.PathEscape(s.ref), &commit); err != nil || commit.ID == "" {
+		return types.Revision{}, types.ErrResourceNotFound
+	}
+	return types.Revision{Ref: s.ref, Commit: commit.ID}, nil
+}
+
+// getCommit returns the latest
+// commit of the branch.
+func (s serverRepository) getCommit(ctx context.Context, name string) (string, error) {
+	query := url.Values{"filterText": {name}}
+	var branches serverBranchList
+	if err := s.client.get(ctx, s.endpoint()+serverBranches+"?"+query.Encode(), &branches); err != nil {
+		return "", types.ErrResourceNotFound
+	}
+	for _, branch := range branches.Values {
+		if branch.DisplayID == name {
+			return branch.LatestCommit, nil
+		}
+	}
+	return "", types.ErrResourceNotFound
+}
+
+// endpoint returns the api
+// url of the repository.
+func (s serverRepository) endpoint() string {
+	return s.baseURL + serverAPIPath + projects + slash + url.PathEscape(s.project) + slash + repos + slash + url.PathEscape(s.repository)
+}
@@ -0,0 +1,187 @@
+/*
+
+Package bitbucket_test is used to test the functionality
+within the bitbucket package.
+
+*/
+package bitbucket_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/bitbucket"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BitbucketServer", func() {
+
+	Context("CreateServer", func() {
+		It("Branch in url", func() {
+			location, _ := giturl.Parse("https://bitbucket.example.com/projects/KEY/repos/slug/browse?at=refs%2Fheads%2Ffeature%2Fdetect")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Owner()).Should(Equal("KEY"), "owner should be 'KEY'")
+			Expect(repositoryService.Repository()).Should(Equal("slug"), "repository should be 'slug'")
+			Expect(repositoryService.Branch()).Should(Equal("feature/detect"), "branch should be 'feature/detect'")
+		})
+
+		It("Personal repository", func() {
+			location, _ := giturl.Parse("https://bitbucket.example.com/users/jdoe/repos/slug")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Owner()).Should(Equal("~jdoe"), "owner should be '~jdoe'")
+			Expect(repositoryService.Branch()).Should(BeEmpty(), "default branch should be resolved")
+		})
+
+		It("Missing repository", func() {
+			location, _ := giturl.Parse("https://bitbucket.example.com/projects")
+			_, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(Equal(types.ErrInvalidPath), "path should be invalid")
+		})
+	})
+
+	Context("DetectBuildTool", func() {
+		var server *httptest.Server
+		ctx := context.TODO()
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/branches", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, `{"values": [{"displayId": "develop-old", "latestCommit": "1a2b3c"}, {"displayId": "develop", "latestCommit": "4d5e6f"}]}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/branches/default", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": "refs/heads/develop", "displayId": "develop", "isDefault": true}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/commits/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": "4d5e6f", "displayId": "4d5e6f"}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/commits/4d5e6f", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": "4d5e6f", "displayId": "4d5e6f"}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/commits/v2.0.0", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": "9e8d7c6", "displayId": "9e8d7c6"}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/browse", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"children": {"values": [{"path": {"toString": "src"}, "type": "DIRECTORY"}, {"path": {"toString": "pom.xml"}, "type": "FILE"}], "isLastPage": true}}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/pull-requests/3", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": 3, "fromRef": {"id": "refs/heads/feature", "latestCommit": "4d5e6f"}}`)
+			})
+			mux.HandleFunc("/bitbucket/rest/api/1.0/projects/KEY/repos/slug/files", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("at") == "9e8d7c6" {
+					fmt.Fprint(w, `{"values": ["src/App.java"], "isLastPage": false, "nextPageStart": 1}`)
+					return
+				}
+				if r.URL.Query().Get("at") != "4d5e6f" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if r.URL.Query().Get("start") == "2" {
+					fmt.Fprint(w, `{"values": ["package.json"], "isLastPage": true}`)
+					return
+				}
+				fmt.Fprint(w, `{"values": ["README.md", "cmd/server/main.go"], "isLastPage": false, "nextPageStart": 2}`)
+			})
+			server = httptest.NewServer(mux)
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("Paginated listing - context path", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug/browse?at=refs/heads/develop")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("nodejs"), "build tool type should be 'nodejs'")
+			Expect(detection.Matches).Should(HaveLen(1), "only nodejs should be detected")
+		})
+
+		It("Default branch", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Branch).Should(Equal("develop"), "default branch should be analyzed")
+		})
+
+		It("Release tag", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug/browse?at=refs/tags/v1.0.0")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("v1.0.0"), "ref should be 'v1.0.0'")
+			Expect(detection.Commit).Should(Equal("4d5e6f"), "commit of the tag should be analyzed")
+		})
+
+		It("Commit in url", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug/browse?at=4d5e6f")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Branch).Should(BeEmpty(), "commit should not be a branch")
+			Expect(detection.Ref).Should(Equal("4d5e6f"), "ref should be the commit")
+			Expect(detection.Commit).Should(Equal("4d5e6f"), "commit should be analyzed")
+		})
+
+		It("Clone url - context path", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/scm/KEY/slug.git")
+			repositoryService, err := bitbucket.CreateServer(location.Server(), *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Owner()).Should(Equal("KEY"), "owner should be 'KEY'")
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Branch).Should(Equal("develop"), "default branch should be analyzed")
+		})
+
+		It("Truncated listing", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug/browse?at=refs/tags/v2.0.0")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "root should be listed separately")
+		})
+
+		It("Pull request", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug/pull-requests/3/overview")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("refs/pull-requests/3/from"), "ref should be the pull request")
+			Expect(detection.Commit).Should(Equal("4d5e6f"), "latest commit of the pull request should be analyzed")
+		})
+
+		It("Branch not found", func() {
+			location, _ := giturl.Parse(server.URL + "/bitbucket/projects/KEY/repos/slug/browse?at=refs/heads/dev")
+			repositoryService, err := bitbucket.CreateServer(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(types.ErrResourceNotFound), "branch should not be found")
+		})
+	})
+})
@@ -0,0 +1,19 @@
+/*
+
+Package bitbucket_test is used to test the functionality
+within the bitbucket package.
+
+*/
+package bitbucket_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBitbucket(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bitbucket Suite")
+}
@@ -0,0 +1,92 @@
+/*
+
+Package bitbucket implements a way to extract
+and construct a request to bitbucket cloud or
+to a self-hosted bitbucket server, in order
+to retrieve the listing of the repository
+files, which the registered detectors are
+evaluated against.
+
+*/
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+const (
+	slash           = "/"
+	repositoryField = "repository"
+	authorization   = "Authorization"
+	bearer          = "Bearer "
+	maxPages        = 50
+	requestTimeout  = 15 * time.Second
+)
+
+// apiClient makes authenticated
+// requests to the bitbucket api.
+type apiClient struct {
+	token string
+	http  *http.Client
+}
+
+// newAPIClient creates an apiClient authenticating
+// with the token, whose requests are recorded in
+// the explanation of their context.
+func newAPIClient(token string) apiClient {
+	return apiClient{
+		token: token,
+		http:  &http.Client{Timeout: requestTimeout, Transport: types.TraceTransport(nil)},
+	}
+}
+
+// get requests the endpoint and decodes
+// the json response into v.
+func (c apiClient) get(ctx context.Context, endpoint string, v interface{}) error {
+	body, err := c.raw(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// raw requests the endpoint and
+// returns the response body.
+func (c apiClient) raw(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(authorization, bearer+c.token)
+
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status " + strconv.Itoa(resp.StatusCode))
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// escapePath escapes each
+// segment of the path.
+func escapePath(path string) string {
+	segments := strings.Split(path, slash)
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, slash)
+}
@@ -1,10 +1,12 @@
 /*
 
 Package github implements a way to extract
-and construct a request to github in order
-to retrieve a pom file. If the pom file is
-not present, we assume the project is not
-build using maven.
+and construct a request to github, or to a
+github enterprise instance, in order to
+retrieve the listing of the repository
+files, which the registered detectors are
+evaluated against. If no marker file is
+present, the build tool is unknown.
 
 */
 package github
@@ -12,65 +14,115 @@ package github
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
 	"github.com/fabric8-services/build-tool-detector/domain/types"
+	"github.com/fabric8-services/build-tool-detector/log"
 	"github.com/google/go-github/github"
 	"golang.org/x/oauth2"
 )
 
 const (
-	master = "master"
-	tree   = "tree"
+	pullRef         = "refs/pull/%d/head"
+	repositoryField = "repository"
+	blobType        = "blob"
+	treeType        = "tree"
 )
 
 var (
 	// ErrFailedContentRetrieval to return if unable to get contents.
-	ErrFailedContentRetrieval = errors.New("unable to retrieve contents")
+	ErrFailedContentRetrieval = types.ErrFailedContentRetrieval
 
 	// ErrUnsupportedGithubURL BadRequest github url is invalid.
 	ErrUnsupportedGithubURL = errors.New("unsupported github url")
 
 	// ErrInvalidPath github url is invalid.
-	ErrInvalidPath = errors.New("url is invalid")
+	ErrInvalidPath = types.ErrInvalidPath
 
 	// ErrResourceNotFound no resource found.
-	ErrResourceNotFound = errors.New("resource not found")
+	ErrResourceNotFound = types.ErrResourceNotFound
+
+	// ErrInvalidEnterpriseURL the configured github
+	// enterprise api url is invalid.
+	ErrInvalidEnterpriseURL = errors.New("invalid github enterprise url")
+
+	// ErrTruncatedTree the listing of the
+	// repository files is incomplete.
+	ErrTruncatedTree = types.ErrTruncatedTree
 )
 
 // RepositoryService contains
 // values pertaining to a github
 // repository.
 type githubRepository struct {
+	baseURL    string
 	owner      string
 	repository string
 	branch     string
+	candidates []string
+	ref        string
+	refType    string
+	location   *giturl.URL
 	token      string
+	precedence []string
 }
 
-// result used to send results to
-// the result channel.
-type result struct {
-	typeInfo *types.BuildType
-	res      *github.Response
-	err      error
+// Create instantiate Github repository
+func Create(location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
+	return newRepository(location, configuration, token)
 }
 
-// Create instantiate Github repository
-func Create(segment []string, branch *string, configuration config.Configuration, token string) (types.RepositoryService, error) {
-	return newRepository(segment, branch, configuration, token)
+// CreateEnterprise instantiate Github Enterprise repository,
+// requests are made to the api at baseURL, as in
+// https://ghe.example.com/api/v3/.
+func CreateEnterprise(baseURL string, location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
+	repositoryService, err := newRepository(location, configuration, token)
+	if err != nil {
+		return repositoryService, err
+	}
+
+	repository := repositoryService.(githubRepository)
+	repository.baseURL = baseURL
+	return repository, nil
+}
+
+// DetectBuildTool gets the contents for the service and returns
+// the detected build tools, pinned by the override file of the
+// repository if any. The build tool type is set to Unknown
+// in case of an error.
+func (g githubRepository) DetectBuildTool(ctx context.Context) (*types.Detection, error) {
+	return types.DetectIn(ctx, g, g.precedence)
 }
 
-// DetectBuildTool gets the contents for the service and returns the buildTool
-// type info. The buildTool type is set to Unknown in case of an error.
-func (g githubRepository) DetectBuildTool(ctx context.Context) (*string, error) {
-	results := getContents(ctx, g)
-	if results.err != nil {
-		buildTool := types.Unknown
-		return &buildTool, results.err
+// Contents returns the files of the repository, rooted
+// at the directory of the url or of the path parameter,
+// along with the revision resolved.
+func (g githubRepository) Contents(ctx context.Context) (*types.Tree, types.Revision, error) {
+	client, err := newClient(ctx, g)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	revision, err := getRevision(ctx, client, g)
+	if err != nil {
+		return nil, types.Revision{}, err
 	}
-	return &results.typeInfo.BuildType, nil
+
+	files, err := getContents(ctx, client, g, revision.Commit)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err = files.Sub(g.location.SubpathOf(revision.Branch))
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	return files, revision, nil
 }
 
 // Owner returns the owner of a repository.
@@ -83,123 +135,232 @@ func (g githubRepository) Repository() string {
 	return g.repository
 }
 
-// Branch returns the repository of a repository.
+// Branch returns the branch of a repository, empty
+// if the default branch is to be resolved or if
+// the repository points at another ref.
 func (g githubRepository) Branch() string {
 	return g.branch
 }
 
-// newRepository will use the path segments and
-// query params to populate the Attributes
-// struct. The attributes struct will be used
-// to make a request to github to determine
-// the build tool type.
-func newRepository(segments []string, ctxBranch *string, configuration config.Configuration, token string) (types.RepositoryService, error) {
+// newRepository will use the parsed url to
+// populate the Attributes struct. The
+// attributes struct will be used to make
+// a request to github to determine the
+// build tool type.
+func newRepository(location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
 	var repositoryService types.RepositoryService
 
-	// Default branch that will be used if a branch
-	// is not passed in though the optional 'branch'
-	// query parameter and is not part of the url.
-	branch := master
-
-	if len(segments) <= 2 {
+	if location == nil || location.Owner == "" || location.Repository == "" {
 		return repositoryService, ErrInvalidPath
 	}
 
-	// If the query parameter field 'branch' is not
-	// empty then set the branch name to the query
-	// parameter value.
-	if ctxBranch != nil {
-		branch = *ctxBranch
-	} else if len(segments) > 4 {
-		// If the user has not specified the branch
-		// check whether it is passed in through
-		// the URL.
-		if segments[3] == tree {
-			branch = segments[4]
-		}
+	// The default branch of the repository is resolved
+	// if the url does not point at a ref. Branches within
+	// the url may contain slashes, every split of the
+	// url is a candidate, shortest first.
+	candidates := location.Candidates()
+	var branch string
+	if len(candidates) > 0 {
+		branch = candidates[0]
 	}
 
 	repositoryService = githubRepository{
-		owner:      segments[1],
-		repository: segments[2],
+		owner:      location.Owner,
+		repository: location.Repository,
 		branch:     branch,
+		candidates: candidates,
+		ref:        location.Ref,
+		refType:    location.RefType,
+		location:   location,
 		token:      token,
+		precedence: configuration.GetDetectorPrecedence(),
 	}
 
 	return repositoryService, nil
 }
 
-// getContents creates a client and
-// initiates making requests to github.
-func getContents(ctx context.Context, repository githubRepository) result {
-
+// newClient creates a github client authenticated
+// with the user token. Enterprise repositories
+// use a client for the enterprise api. Requests
+// are recorded in the explanation of their context.
+func newClient(ctx context.Context, repository githubRepository) (*github.Client, error) {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: repository.token},
 	)
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: types.TraceTransport(nil)})
 	tc := oauth2.NewClient(ctx, ts)
+	if repository.baseURL == "" {
+		return github.NewClient(tc), nil
+	}
 
-	// If the github client id or github client
-	// secret are empty, we will log and fail.
-	client := github.NewClient(tc)
+	client, err := github.NewEnterpriseClient(repository.baseURL, repository.baseURL, tc)
+	if err != nil {
+		return nil, ErrInvalidEnterpriseURL
+	}
+	return client, nil
+}
 
-	_, err := getBranchRequest(ctx, client, repository)
+// getContents fetches the listing of all files of
+// the branch in a single request, the detectors are
+// evaluated against the listing so the number of
+// requests does not grow with the build types.
+// File contents are only fetched on demand.
+func getContents(ctx context.Context, client *github.Client, repository githubRepository, sha string) (*types.Tree, error) {
+	gitTree, err := getTreeRequest(ctx, client, repository, sha, true)
 	if err != nil {
-		return result{nil, nil, err}
+		return nil, err
 	}
 
-	// Parallel get requests.
-	results := getContentsRequest(ctx, types.GetTypes(), client, repository)
-	for _, result := range results {
-		if result.res != nil {
-			if result.res.StatusCode == http.StatusOK {
-				return result
-			}
+	// A truncated listing may miss the marker files
+	// at the root of the repository, which are
+	// therefore listed separately.
+	entries := gitTree.Entries
+	if gitTree.GetTruncated() {
+		log.Logger().WithField(repositoryField, repository.repository).Warnf(ErrTruncatedTree.Error())
+		rootTree, err := getTreeRequest(ctx, client, repository, sha, false)
+		if err != nil {
+			return nil, err
 		}
+		entries = append(entries, rootTree.Entries...)
 	}
 
-	return result{nil, nil, ErrFailedContentRetrieval}
+	blobs := blobSource{
+		client:     client,
+		repository: repository,
+		shas:       make(map[string]string, len(entries)),
+	}
+	var listing []types.Entry
+	for _, entry := range entries {
+		switch entry.GetType() {
+		case blobType:
+			blobs.shas[entry.GetPath()] = entry.GetSHA()
+			listing = append(listing, types.Entry{Path: entry.GetPath()})
+		case treeType:
+			listing = append(listing, types.Entry{Path: entry.GetPath(), Dir: true})
+		}
+	}
+	return types.NewTree(listing, blobs.fetch), nil
 }
 
-// getBranchRequest makes a request
-// to ensure the repository and
-// branch are valid.
-func getBranchRequest(ctx context.Context, client *github.Client, repository githubRepository) (bool, error) {
-	_, _, err := client.Repositories.GetBranch(ctx, repository.owner, repository.repository, repository.branch)
+// getRevision resolves the commit the build tools
+// are detected at, from the branch, the ref or the
+// pull request the repository points at.
+func getRevision(ctx context.Context, client *github.Client, repository githubRepository) (types.Revision, error) {
+	switch {
+	case repository.refType == giturl.PullRequest:
+		return getPullRequest(ctx, client, repository)
+	case repository.refType != giturl.Branch && repository.ref != "":
+		return getRefRequest(ctx, client, repository)
+	}
+
+	if repository.branch == "" {
+		defaultBranch, err := getDefaultBranchRequest(ctx, client, repository)
+		if err != nil {
+			return types.Revision{}, err
+		}
+		repository.branch = defaultBranch
+	}
+
+	branch, err := getBranchRequest(ctx, client, repository)
+	// The ref of /tree/<ref> urls may be
+	// a tag or a commit rather than a branch.
+	if err != nil && len(repository.candidates) > 0 {
+		return getRefRequest(ctx, client, repository)
+	}
 	if err != nil {
-		return false, ErrResourceNotFound
+		return types.Revision{}, err
 	}
+	return types.Revision{Branch: branch.GetName(), Commit: branch.GetCommit().GetSHA()}, nil
+}
 
-	return true, nil
+// getRefRequest makes a request to resolve the
+// commit of the ref, a tag or a commit sha.
+func getRefRequest(ctx context.Context, client *github.Client, repository githubRepository) (types.Revision, error) {
+	sha, _, err := client.Repositories.GetCommitSHA1(ctx, repository.owner, repository.repository, repository.ref, "")
+	if err != nil {
+		return types.Revision{}, ErrResourceNotFound
+	}
+	return types.Revision{Ref: repository.ref, Commit: sha}, nil
 }
 
-// getContentsRequest makes parellel requests
-// and sends the results through a results channel.
-func getContentsRequest(ctx context.Context, buildTypes []types.BuildType, client *github.Client, repository githubRepository) []result {
-	resultsChannel := make(chan result)
-	defer func() {
-		close(resultsChannel)
-	}()
+// getPullRequest makes a request to resolve
+// the head commit of the pull request.
+func getPullRequest(ctx context.Context, client *github.Client, repository githubRepository) (types.Revision, error) {
+	number, err := strconv.Atoi(repository.ref)
+	if err != nil {
+		return types.Revision{}, ErrInvalidPath
+	}
 
-	for _, buildType := range buildTypes {
-		go func(buildType types.BuildType) {
-			_, _, resp, err := client.Repositories.GetContents(
-				ctx, repository.owner,
-				repository.repository,
-				buildType.File,
-				&github.RepositoryContentGetOptions{Ref: repository.branch})
-			resultsChannel <- result{&buildType, resp, err}
-		}(buildType)
+	pullRequest, _, err := client.PullRequests.Get(ctx, repository.owner, repository.repository, number)
+	if err != nil {
+		return types.Revision{}, ErrResourceNotFound
 	}
+	return types.Revision{Ref: fmt.Sprintf(pullRef, number), Commit: pullRequest.GetHead().GetSHA()}, nil
+}
 
-	var results []result
-	for {
-		result := <-resultsChannel
-		results = append(results, result)
+// getBranchRequest makes a request
+// to ensure the repository and
+// branch are valid. The candidate
+// branches are requested in order.
+func getBranchRequest(ctx context.Context, client *github.Client, repository githubRepository) (*github.Branch, error) {
+	candidates := repository.candidates
+	if len(candidates) == 0 {
+		candidates = []string{repository.branch}
+	}
 
-		// if we've reached the expected amount of urls then stop
-		if len(results) == len(buildTypes) {
-			break
+	for _, candidate := range candidates {
+		branch, _, err := client.Repositories.GetBranch(ctx, repository.owner, repository.repository, candidate)
+		if err != nil {
+			continue
 		}
+		if branch.GetName() == "" {
+			branch.Name = github.String(candidate)
+		}
+		return branch, nil
+	}
+	return nil, ErrResourceNotFound
+}
+
+// getDefaultBranchRequest makes a request to
+// resolve the default branch of the repository.
+func getDefaultBranchRequest(ctx context.Context, client *github.Client, repository githubRepository) (string, error) {
+	repo, _, err := client.Repositories.Get(ctx, repository.owner, repository.repository)
+	if err != nil || repo.GetDefaultBranch() == "" {
+		return "", ErrResourceNotFound
+	}
+
+	return repo.GetDefaultBranch(), nil
+}
+
+// getTreeRequest makes a request to
+// list the files of the commit.
+func getTreeRequest(ctx context.Context, client *github.Client, repository githubRepository, sha string, recursive bool) (*github.Tree, error) {
+	gitTree, _, err := client.Git.GetTree(ctx, repository.owner, repository.repository, sha, recursive)
+	if err != nil {
+		return nil, ErrFailedContentRetrieval
+	}
+
+	return gitTree, nil
+}
+
+// blobSource fetches the contents of the
+// files listed in the tree by blob sha.
+type blobSource struct {
+	client     *github.Client
+	repository githubRepository
+	shas       map[string]string
+}
+
+// fetch returns the raw contents of the file.
+func (b blobSource) fetch(ctx context.Context, path string) ([]byte, error) {
+	sha, ok := b.shas[path]
+	if !ok {
+		return nil, types.ErrFileNotFound
+	}
+
+	content, _, err := b.client.Git.GetBlobRaw(ctx, b.repository.owner, b.repository.repository, sha)
+	if err != nil {
+		return nil, ErrFailedContentRetrieval
 	}
-	return results
+	return content, nil
 }
@@ -7,14 +7,389 @@ within the github package.
 package github_test
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/github"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/h2non/gock.v1"
 )
 
 var _ = Describe("GithubService", func() {
 
-	Context("GetGitServiceType", func() {
-		It("Faulty Host - empty", func() {
-			//GetContents()
+	Context("DetectBuildTool", func() {
+		ctx := context.TODO()
+		location, _ := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend")
+
+		BeforeEach(func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_launcher_backend/ok_repo.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend$").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../../../controllers/test/mock/fabric8_launcher_backend/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../../../controllers/test/mock/fabric8_launcher_backend/ok_tree_polyglot.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				MatchParam("recursive", "1").
+				Reply(200).
+				BodyString(string(bodyString))
+		})
+		AfterEach(func() {
+			os.Unsetenv("BUILD_TOOL_DETECTOR_DETECTOR_PRECEDENCE")
+			gock.Off()
+		})
+
+		It("Several marker files - default precedence", func() {
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "build tool type should be 'maven'")
+			Expect(detection.Branch).Should(Equal("master"), "default branch should be analyzed")
+			Expect(detection.Matches).Should(HaveLen(2), "maven and nodejs should be detected")
+			Expect(detection.Matches[1].BuildType).Should(Equal("nodejs"), "second build tool type should be 'nodejs'")
+			Expect(detection.Matches[1].Evidence).Should(Equal("package.json"), "evidence should be 'package.json'")
+		})
+
+		It("Several marker files - configured precedence", func() {
+			os.Setenv("BUILD_TOOL_DETECTOR_DETECTOR_PRECEDENCE", "nodejs,maven")
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("nodejs"), "build tool type should be 'nodejs'")
+		})
+
+		It("Several marker files - single tree request", func() {
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(gock.IsDone()).Should(BeTrue(), "only the repository, branch and tree should be requested")
+		})
+	})
+
+	Context("DetectBuildTool - contents on demand", func() {
+		ctx := context.TODO()
+		location, _ := giturl.Parse("https://github.com/fabric8-services/fabric8-wit")
+
+		BeforeEach(func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_wit/ok_repo.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit$").
+				Reply(200).
+				BodyString(string(bodyString))
+		})
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Go module - go.mod fetched", func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_wit/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
+				Reply(200).
+				BodyString(`{"sha": "c298aa09d67c945857c020f5541720898b630103", "tree": [{"path": "go.mod", "type": "blob", "sha": "f1c2"}, {"path": "cmd", "type": "tree", "sha": "d4e5"}], "truncated": false}`)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/blobs/f1c2").
+				Reply(200).
+				BodyString("module github.com/fabric8-services/fabric8-wit\n\ngo 1.11\n")
+
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("golang"), "build tool type should be 'golang'")
+			Expect(detection.Top().Golang.Module).Should(Equal("github.com/fabric8-services/fabric8-wit"), "module should be read from go.mod")
+			Expect(gock.IsDone()).Should(BeTrue(), "go.mod should be fetched")
+		})
+
+		It("Override file", func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_wit/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
+				Reply(200).
+				BodyString(`{"tree": [{"path": ".build-tool-detector.yaml", "type": "blob", "sha": "b7a1"}, {"path": "main.go", "type": "blob", "sha": "c3d4"}], "truncated": false}`)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/blobs/b7a1").
+				Reply(200).
+				BodyString("build-tool: golang\nbuilder-image: golang:1.11\n")
+
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Overridden).Should(BeTrue(), "detection should be overridden")
+			Expect(detection.BuilderImage).Should(Equal("golang:1.11"), "builder image should be read from the override file")
+			Expect(gock.IsDone()).Should(BeTrue(), "override file should be fetched")
+		})
+
+		It("Explanation", func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_wit/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
+				Reply(200).
+				BodyString(`{"tree": [{"path": "pom.xml", "type": "blob", "sha": "a1b2"}, {"path": "package.json", "type": "blob", "sha": "c3d4"}], "truncated": false}`)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/blobs/a1b2").
+				Reply(200).
+				BodyString("<project><artifactId>wit</artifactId></project>")
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/blobs/c3d4").
+				Reply(404)
+
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			explainCtx, explanation := types.WithExplanation(ctx)
+			detection, err := repositoryService.DetectBuildTool(explainCtx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal(types.Maven), "build tool type should be maven")
+			Expect(explanation.Requests[1].URL).Should(HaveSuffix("/branches/master"), "branch should be requested after the default branch")
+			Expect(explanation.Requests[1].Status).Should(Equal(200), "branch status should be recorded")
+			Expect(explanation.Requests[len(explanation.Requests)-1].Status).Should(Equal(404), "blob status should be recorded")
+			Expect(explanation.Probes).Should(HaveLen(len(types.Detectors())), "every build type should be probed")
+			Expect(explanation.Probes[0].Matched).Should(BeTrue(), "maven should be matched")
+			Expect(explanation.Decision).Should(Equal("maven takes precedence over nodejs having the highest detector priority"))
+		})
+
+		It("No marker files - unknown", func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_wit/ok_branch.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/branches/master").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			bodyString, err = ioutil.ReadFile("../../../controllers/test/mock/fabric8_wit/unknown_tree.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0").
+				Reply(200).
+				BodyString(string(bodyString))
+
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(github.ErrFailedContentRetrieval))
+			Expect(detection.BuildType).Should(Equal("unknown"), "build tool type should be 'unknown'")
+		})
+	})
+
+	Context("DetectBuildTool - branch containing slashes", func() {
+		ctx := context.TODO()
+
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Shortest branch not found", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/feature$").
+				Reply(404)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/feature/detect$").
+				Reply(200).
+				BodyString(`{"name": "feature/detect", "commit": {"sha": "a2eb145933e1044956aa96fac4945be37970ed19"}}`)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(`{"tree": [{"path": "pom.xml", "type": "blob"}, {"path": "web", "type": "tree"}, {"path": "web/package.json", "type": "blob"}], "truncated": false}`)
+
+			location, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/feature/detect/web")
+			Expect(err).Should(BeNil())
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Branch).Should(Equal("feature/detect"), "branch should contain slashes")
+			Expect(detection.Path).Should(Equal("web"), "path should follow the branch")
+			Expect(detection.BuildType).Should(Equal("nodejs"), "build tools of 'web' should be detected")
+		})
+
+		It("No branch found", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/").
+				Times(2).
+				Reply(404)
+
+			location, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/blob/feature/pom.xml")
+			Expect(err).Should(BeNil())
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(github.ErrResourceNotFound), "every candidate branch should be requested")
+			Expect(gock.IsDone()).Should(BeTrue(), "every candidate branch should be requested")
+		})
+	})
+
+	Context("DetectBuildTool - ref", func() {
+		ctx := context.TODO()
+
+		BeforeEach(func() {
+			bodyString, err := ioutil.ReadFile("../../../controllers/test/mock/fabric8_launcher_backend/ok_tree.json")
+			Expect(err).Should(BeNil())
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/git/trees/a2eb145933e1044956aa96fac4945be37970ed19").
+				Reply(200).
+				BodyString(string(bodyString))
+		})
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Release tag", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/commits/v1.0.0").
+				Reply(200).
+				BodyString("a2eb145933e1044956aa96fac4945be37970ed19")
+
+			location, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/releases/tag/v1.0.0")
+			Expect(err).Should(BeNil())
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "build tool type should be 'maven'")
+			Expect(detection.Ref).Should(Equal("v1.0.0"), "ref should be 'v1.0.0'")
+			Expect(detection.Commit).Should(Equal("a2eb145933e1044956aa96fac4945be37970ed19"), "commit of the tag should be analyzed")
+			Expect(detection.Branch).Should(BeEmpty(), "no branch should be analyzed")
+		})
+
+		It("Commit in /tree/ url", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/branches/a2eb145").
+				Reply(404)
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/commits/a2eb145").
+				Reply(200).
+				BodyString("a2eb145933e1044956aa96fac4945be37970ed19")
+
+			location, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/tree/a2eb145")
+			Expect(err).Should(BeNil())
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("a2eb145"), "commit should be resolved once no branch matched")
+			Expect(detection.Commit).Should(Equal("a2eb145933e1044956aa96fac4945be37970ed19"), "commit should be analyzed")
+			Expect(detection.Branch).Should(BeEmpty(), "no branch should be analyzed")
+		})
+
+		It("Pull request", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/pulls/123$").
+				Reply(200).
+				BodyString(`{"number": 123, "head": {"ref": "feature", "sha": "a2eb145933e1044956aa96fac4945be37970ed19"}}`)
+
+			location, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/pull/123")
+			Expect(err).Should(BeNil())
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("refs/pull/123/head"), "ref should be the pull request head")
+			Expect(detection.Commit).Should(Equal("a2eb145933e1044956aa96fac4945be37970ed19"), "head of the pull request should be analyzed")
+		})
+
+		It("Pull request not found", func() {
+			gock.New("https://api.github.com").
+				Get("/repos/fabric8-launcher/launcher-backend/pulls/124$").
+				Reply(404)
+
+			location, err := giturl.Parse("https://github.com/fabric8-launcher/launcher-backend/pull/124")
+			Expect(err).Should(BeNil())
+			repositoryService, err := github.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(github.ErrResourceNotFound), "pull request should not be found")
+		})
+	})
+
+	Context("DetectBuildTool - enterprise", func() {
+		var server *httptest.Server
+		ctx := context.TODO()
+		location, _ := giturl.Parse("https://github.com/fabric8-services/fabric8-wit")
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/fabric8-services/fabric8-wit", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"name": "fabric8-wit", "default_branch": "main"}`)
+			})
+			mux.HandleFunc("/api/v3/repos/fabric8-services/fabric8-wit/branches/main", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"name": "main", "commit": {"sha": "cd7a01bc85da4d639239e143771bdab76a64c0b0"}}`)
+			})
+			mux.HandleFunc("/api/v3/repos/fabric8-services/fabric8-wit/git/trees/cd7a01bc85da4d639239e143771bdab76a64c0b0", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"tree": [{"path": "package.json", "type": "blob", "sha": "a1b2"}], "truncated": false}`)
+			})
+			server = httptest.NewServer(mux)
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("Enterprise api", func() {
+			repositoryService, err := github.CreateEnterprise(server.URL+"/api/v3/", location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("nodejs"), "build tool type should be 'nodejs'")
+			Expect(detection.Branch).Should(Equal("main"), "default branch should be analyzed")
+		})
+
+		It("Invalid enterprise api url", func() {
+			repositoryService, err := github.CreateEnterprise("://ghe", location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(github.ErrInvalidEnterpriseURL))
 		})
 	})
 })
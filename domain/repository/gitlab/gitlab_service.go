@@ -0,0 +1,389 @@
+/*
+
+Package gitlab implements a way to extract
+and construct a request to gitlab, gitlab.com
+or self-hosted, in order to retrieve the
+listing of the repository files, which the
+registered detectors are evaluated against.
+
+*/
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	"github.com/fabric8-services/build-tool-detector/log"
+)
+
+const (
+	slash           = "/"
+	blobType        = "blob"
+	treeType        = "tree"
+	repositoryField = "repository"
+
+	apiPath           = "/api/v4/projects/"
+	branchesPath      = "/repository/branches/"
+	commitsPath       = "/repository/commits/"
+	mergeRequestsPath = "/merge_requests/"
+	mergeRequestRef   = "refs/merge-requests/%s/head"
+	treePath          = "/repository/tree"
+	filesPath         = "/repository/files/"
+	rawPath           = "/raw"
+	authorization     = "Authorization"
+	bearer            = "Bearer "
+	nextPage          = "X-Next-Page"
+	perPage           = "100"
+	maxPages          = 50
+	requestTimeout    = 15 * time.Second
+)
+
+// gitlabRepository contains
+// values pertaining to a gitlab
+// project.
+type gitlabRepository struct {
+	baseURL    string
+	owner      string
+	repository string
+	branch     string
+	candidates []string
+	ref        string
+	refType    string
+	location   *giturl.URL
+	token      string
+	precedence []string
+	client     *http.Client
+}
+
+// branch is the subset of the gitlab
+// branch resource used for detection.
+type branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+// project is the subset of the gitlab
+// project resource used for detection.
+type project struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+// commit is the subset of the gitlab
+// commit resource used for detection.
+type commit struct {
+	ID string `json:"id"`
+}
+
+// mergeRequest is the subset of the gitlab
+// merge request resource used for detection.
+type mergeRequest struct {
+	SHA string `json:"sha"`
+}
+
+// treeEntry is a file or directory
+// of the repository tree.
+type treeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// Create instantiate Gitlab repository, the url
+// is expected to be parsed with nested groups.
+func Create(location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
+	return newRepository(location, configuration, token)
+}
+
+// DetectBuildTool gets the contents for the service and returns
+// the detected build tools, pinned by the override file of the
+// repository if any. The build tool type is set to Unknown
+// in case of an error.
+func (g gitlabRepository) DetectBuildTool(ctx context.Context) (*types.Detection, error) {
+	return types.DetectIn(ctx, g, g.precedence)
+}
+
+// Contents returns the files of the project, rooted at
+// the requested directory, and the revision resolved.
+func (g gitlabRepository) Contents(ctx context.Context) (*types.Tree, types.Revision, error) {
+	revision, err := getRevision(ctx, g)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err := getContents(ctx, g, revision.Commit)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err = files.Sub(g.location.SubpathOf(revision.Branch))
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	return files, revision, nil
+}
+
+// Owner returns the namespace of a project,
+// including any nested groups.
+func (g gitlabRepository) Owner() string {
+	return g.owner
+}
+
+// Repository returns the name of a project.
+func (g gitlabRepository) Repository() string {
+	return g.repository
+}
+
+// Branch returns the branch of a project, empty
+// if the default branch is to be resolved or if
+// the project points at another ref.
+func (g gitlabRepository) Branch() string {
+	return g.branch
+}
+
+// newRepository will use the parsed url to
+// populate the gitlabRepository struct.
+// Projects can be nested in groups, the
+// branch is either passed through the optional
+// 'branch' query parameter or part of the url,
+// as in /group/project/-/tree/branch.
+func newRepository(location *giturl.URL, configuration config.Configuration, token string) (types.RepositoryService, error) {
+	var repositoryService types.RepositoryService
+
+	if location == nil || location.Owner == "" || location.Repository == "" {
+		return repositoryService, types.ErrInvalidPath
+	}
+
+	// The default branch of the project is resolved
+	// if the url does not point at a ref. Branches
+	// within the url may contain slashes, every
+	// split of the url is a candidate.
+	candidates := location.Candidates()
+	var branch string
+	if len(candidates) > 0 {
+		branch = candidates[0]
+	}
+
+	repositoryService = gitlabRepository{
+		baseURL:    location.BaseURL(),
+		owner:      location.Owner,
+		repository: location.Repository,
+		branch:     branch,
+		candidates: candidates,
+		ref:        location.Ref,
+		refType:    location.RefType,
+		location:   location,
+		token:      token,
+		precedence: configuration.GetDetectorPrecedence(),
+		client:     &http.Client{Timeout: requestTimeout, Transport: types.TraceTransport(nil)},
+	}
+	return repositoryService, nil
+}
+
+// project returns the path of the
+// project, including the namespace.
+func (g gitlabRepository) project() string {
+	return g.owner + slash + g.repository
+}
+
+// getContents fetches the listing of all files of the
+// branch, the detectors are evaluated against the
+// listing. File contents are only fetched on demand.
+func getContents(ctx context.Context, repository gitlabRepository, sha string) (*types.Tree, error) {
+	entries, truncated, err := getTreeRequest(ctx, repository, sha, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Past the page limit, the listing may miss the
+	// marker files at the root of the project, which
+	// are therefore listed separately.
+	if truncated {
+		log.Logger().WithField(repositoryField, repository.project()).Warnf(types.ErrTruncatedTree.Error())
+		rootEntries, _, err := getTreeRequest(ctx, repository, sha, false)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, rootEntries...)
+	}
+
+	var listing []types.Entry
+	for _, entry := range entries {
+		switch entry.Type {
+		case blobType:
+			listing = append(listing, types.Entry{Path: entry.Path})
+		case treeType:
+			listing = append(listing, types.Entry{Path: entry.Path, Dir: true})
+		}
+	}
+
+	files := fileSource{repository: repository, ref: sha}
+	return types.NewTree(listing, files.fetch), nil
+}
+
+// getRevision resolves the commit the build tools
+// are detected at, from the branch, the ref or the
+// merge request the project points at.
+func getRevision(ctx context.Context, repository gitlabRepository) (types.Revision, error) {
+	switch {
+	case repository.refType == giturl.PullRequest:
+		var m mergeRequest
+		if _, err := repository.get(ctx, mergeRequestsPath+url.PathEscape(repository.ref), nil, &m); err != nil || m.SHA == "" {
+			return types.Revision{}, types.ErrResourceNotFound
+		}
+		return types.Revision{Ref: fmt.Sprintf(mergeRequestRef, repository.ref), Commit: m.SHA}, nil
+	case repository.refType != giturl.Branch && repository.ref != "":
+		return getRefRequest(ctx, repository)
+	}
+
+	candidates := repository.candidates
+	if len(candidates) == 0 {
+		defaultBranch, err := getDefaultBranchRequest(ctx, repository)
+		if err != nil {
+			return types.Revision{}, err
+		}
+		candidates = []string{defaultBranch}
+	}
+
+	b, err := getBranchRequest(ctx, repository, candidates)
+	// The ref of /tree/<ref> urls may be
+	// a tag or a commit rather than a branch.
+	if err != nil && len(repository.candidates) > 0 {
+		return getRefRequest(ctx, repository)
+	}
+	if err != nil {
+		return types.Revision{}, err
+	}
+	return types.Revision{Branch: b.Name, Commit: b.Commit.ID}, nil
+}
+
+// getRefRequest makes a request to resolve the
+// commit of the ref, a tag or a commit sha.
+func getRefRequest(ctx context.Context, repository gitlabRepository) (types.Revision, error) {
+	var c commit
+	if _, err := repository.get(ctx, commitsPath+url.PathEscape(repository.ref), nil, &c); err != nil || c.ID == "" {
+		return types.Revision{}, types.ErrResourceNotFound
+	}
+	return types.Revision{Ref: repository.ref, Commit: c.ID}, nil
+}
+
+// getBranchRequest makes a request
+// to ensure the project and
+// branch are valid. The candidate
+// branches are requested in order.
+func getBranchRequest(ctx context.Context, repository gitlabRepository, candidates []string) (*branch, error) {
+	for _, candidate := range candidates {
+		var b branch
+		if _, err := repository.get(ctx, branchesPath+url.PathEscape(candidate), nil, &b); err != nil {
+			continue
+		}
+		if b.Name == "" {
+			b.Name = candidate
+		}
+		return &b, nil
+	}
+	return nil, types.ErrResourceNotFound
+}
+
+// getDefaultBranchRequest makes a request to
+// resolve the default branch of the project.
+func getDefaultBranchRequest(ctx context.Context, repository gitlabRepository) (string, error) {
+	var p project
+	_, err := repository.get(ctx, "", nil, &p)
+	if err != nil || p.DefaultBranch == "" {
+		return "", types.ErrResourceNotFound
+	}
+	return p.DefaultBranch, nil
+}
+
+// getTreeRequest makes paginated requests to list
+// the files of the commit, or of its root unless
+// recursive. Whether the listing was truncated at
+// the page limit is returned along with it.
+func getTreeRequest(ctx context.Context, repository gitlabRepository, ref string, recursive bool) ([]treeEntry, bool, error) {
+	var entries []treeEntry
+	page := "1"
+	for i := 0; i < maxPages && page != ""; i++ {
+		var pageEntries []treeEntry
+		query := url.Values{
+			"ref":      {ref},
+			"per_page": {perPage},
+			"page":     {page},
+		}
+		if recursive {
+			query.Set("recursive", "true")
+		}
+		header, err := repository.get(ctx, treePath, query, &pageEntries)
+		if err != nil {
+			return nil, false, types.ErrFailedContentRetrieval
+		}
+		entries = append(entries, pageEntries...)
+		page = header.Get(nextPage)
+	}
+	return entries, page != "", nil
+}
+
+// get makes an authenticated request to the project
+// api and decodes the json response into v. The
+// response headers are returned.
+func (g gitlabRepository) get(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error) {
+	body, header, err := g.do(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+	return header, json.Unmarshal(body, v)
+}
+
+// do makes an authenticated request to the
+// project api and returns the response body.
+func (g gitlabRepository) do(ctx context.Context, path string, query url.Values) ([]byte, http.Header, error) {
+	endpoint := g.baseURL + apiPath + url.PathEscape(g.project()) + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set(authorization, bearer+g.token)
+
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.New("unexpected status " + strconv.Itoa(resp.StatusCode))
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp.Header, err
+}
+
+// fileSource fetches the raw contents
+// of the files listed in the tree.
+type fileSource struct {
+	repository gitlabRepository
+	ref        string
+}
+
+// fetch returns the raw contents of the file.
+func (f fileSource) fetch(ctx context.Context, path string) ([]byte, error) {
+	content, _, err := f.repository.do(ctx, filesPath+url.PathEscape(path)+rawPath, url.Values{"ref": {f.ref}})
+	if err != nil {
+		return nil, types.ErrFailedContentRetrieval
+	}
+	return content, nil
+}
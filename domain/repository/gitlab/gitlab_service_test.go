@@ -0,0 +1,258 @@
+/*
+Package gitlab_test is used to test the functionality
+within the gitlab package.
+*/
+package gitlab_test
+
+import (
+	"context"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/gitlab"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// projectPath is the decoded path of the project api,
+// gock matches requests against the decoded path.
+const (
+	projectPath = "/api/v4/projects/group/subgroup/project"
+	commitID    = "0b4bc9a49b562e85de7cc9e834518ea6828729b9"
+)
+
+// parse parses the url of a project
+// which may be nested in groups.
+func parse(raw string) *giturl.URL {
+	location, err := giturl.Parse(raw)
+	Expect(err).Should(BeNil())
+	return location.Nested()
+}
+
+var _ = Describe("GitlabService", func() {
+
+	Context("Create", func() {
+		It("Nested groups - default branch", func() {
+			location := parse("https://gitlab.com/group/subgroup/project")
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Owner()).Should(Equal("group/subgroup"), "owner should be 'group/subgroup'")
+			Expect(repositoryService.Repository()).Should(Equal("project"), "repository should be 'project'")
+			Expect(repositoryService.Branch()).Should(BeEmpty(), "default branch should be resolved")
+		})
+
+		It("Branch in url", func() {
+			location := parse("https://gitlab.com/group/subgroup/project/-/tree/develop")
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Owner()).Should(Equal("group/subgroup"), "owner should be 'group/subgroup'")
+			Expect(repositoryService.Branch()).Should(Equal("develop"), "branch should be 'develop'")
+		})
+
+		It("Branch in legacy url", func() {
+			location := parse("https://gitlab.com/group/project/tree/develop")
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Repository()).Should(Equal("project"), "repository should be 'project'")
+			Expect(repositoryService.Branch()).Should(Equal("develop"), "branch should be 'develop'")
+		})
+
+		It("Branch query parameter takes precedence", func() {
+			location := parse("https://gitlab.com/group/project/-/tree/develop").WithBranch("release")
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+			Expect(repositoryService.Branch()).Should(Equal("release"), "branch should be 'release'")
+		})
+
+		It("Missing project", func() {
+			location, _ := giturl.Parse("https://gitlab.com/group")
+			_, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(Equal(types.ErrInvalidPath), "path should be invalid")
+		})
+	})
+
+	Context("DetectBuildTool", func() {
+		ctx := context.TODO()
+		location, _ := giturl.Parse("https://gitlab.com/group/subgroup/project")
+		location = location.Nested()
+
+		BeforeEach(func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath+"$").
+				MatchHeader("Authorization", "Bearer token").
+				Reply(200).
+				BodyString(`{"path_with_namespace": "group/subgroup/project", "default_branch": "main"}`)
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/branches/main").
+				MatchHeader("Authorization", "Bearer token").
+				Reply(200).
+				BodyString(`{"name": "main", "commit": {"id": "` + commitID + `"}}`)
+		})
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Paginated tree", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/tree").
+				MatchParam("ref", commitID).
+				MatchParam("recursive", "true").
+				MatchParam("page", "1").
+				Reply(200).
+				SetHeader("X-Next-Page", "2").
+				BodyString(`[{"path": "src", "type": "tree"}, {"path": "README.md", "type": "blob"}]`)
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/tree").
+				MatchParam("page", "2").
+				Reply(200).
+				BodyString(`[{"path": "package.json", "type": "blob"}, {"path": "go.mod", "type": "blob"}]`)
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/files/go.mod/raw").
+				MatchParam("ref", commitID).
+				Reply(200).
+				BodyString("module gitlab.com/group/subgroup/project\n\ngo 1.12\n")
+
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("nodejs"), "build tool type should be 'nodejs'")
+			Expect(detection.Branch).Should(Equal("main"), "default branch should be analyzed")
+			Expect(detection.Matches).Should(HaveLen(2), "nodejs and golang should be detected")
+			Expect(detection.Matches[1].Golang.Module).Should(Equal("gitlab.com/group/subgroup/project"), "module should be read from go.mod")
+			Expect(gock.IsDone()).Should(BeTrue(), "every page should be requested")
+		})
+
+		It("Truncated tree", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/tree").
+				MatchParam("recursive", "true").
+				Times(50).
+				Reply(200).
+				SetHeader("X-Next-Page", "2").
+				BodyString(`[{"path": "src/main/java/App.java", "type": "blob"}]`)
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/tree").
+				MatchParam("ref", commitID).
+				Reply(200).
+				BodyString(`[{"path": "src", "type": "tree"}, {"path": "pom.xml", "type": "blob"}]`)
+
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "root should be listed separately")
+			Expect(gock.IsDone()).Should(BeTrue(), "the root should be requested past the page limit")
+		})
+
+		It("Unknown build tool", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/repository/tree").
+				Reply(200).
+				BodyString(`[{"path": "README.md", "type": "blob"}]`)
+
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(types.ErrFailedContentRetrieval), "no build tool should be detected")
+			Expect(detection.BuildType).Should(Equal("unknown"), "build tool type should be 'unknown'")
+		})
+	})
+
+	Context("DetectBuildTool - ref", func() {
+		ctx := context.TODO()
+
+		BeforeEach(func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath+"/repository/tree").
+				MatchParam("ref", commitID).
+				Reply(200).
+				BodyString(`[{"path": "pom.xml", "type": "blob"}]`)
+		})
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Release tag", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/repository/commits/v1.0.0").
+				Reply(200).
+				BodyString(`{"id": "` + commitID + `"}`)
+
+			repositoryService, err := gitlab.Create(parse("https://gitlab.com/group/subgroup/project/-/tags/v1.0.0"), *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(Equal("maven"), "build tool type should be 'maven'")
+			Expect(detection.Ref).Should(Equal("v1.0.0"), "ref should be 'v1.0.0'")
+			Expect(detection.Commit).Should(Equal(commitID), "commit of the tag should be analyzed")
+			Expect(detection.Branch).Should(BeEmpty(), "no branch should be analyzed")
+		})
+
+		It("Tag in /tree/ url", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/repository/branches/v1.0.0").
+				Reply(404).
+				BodyString(`{"message": "404 Branch Not Found"}`)
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/repository/commits/v1.0.0").
+				Reply(200).
+				BodyString(`{"id": "` + commitID + `"}`)
+
+			repositoryService, err := gitlab.Create(parse("https://gitlab.com/group/subgroup/project/-/tree/v1.0.0"), *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("v1.0.0"), "tag should be resolved once no branch matched")
+			Expect(detection.Commit).Should(Equal(commitID), "commit of the tag should be analyzed")
+		})
+
+		It("Merge request", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/merge_requests/7$").
+				Reply(200).
+				BodyString(`{"iid": 7, "sha": "` + commitID + `"}`)
+
+			repositoryService, err := gitlab.Create(parse("https://gitlab.com/group/subgroup/project/-/merge_requests/7"), *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			detection, err := repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(BeNil())
+			Expect(detection.Ref).Should(Equal("refs/merge-requests/7/head"), "ref should be the merge request head")
+			Expect(detection.Commit).Should(Equal(commitID), "head of the merge request should be analyzed")
+		})
+	})
+
+	Context("DetectBuildTool - missing branch", func() {
+		ctx := context.TODO()
+
+		AfterEach(func() {
+			gock.Off()
+		})
+
+		It("Branch not found", func() {
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/repository/branches/missing").
+				Reply(404).
+				BodyString(`{"message": "404 Branch Not Found"}`)
+			gock.New("https://gitlab.com").
+				Get(projectPath + "/repository/commits/missing").
+				Reply(404).
+				BodyString(`{"message": "404 Commit Not Found"}`)
+
+			location := parse("https://gitlab.com/group/subgroup/project/-/tree/missing")
+			repositoryService, err := gitlab.Create(location, *config.New(), "token")
+			Expect(err).Should(BeNil())
+
+			_, err = repositoryService.DetectBuildTool(ctx)
+			Expect(err).Should(Equal(types.ErrResourceNotFound), "branch should not be found")
+		})
+	})
+})
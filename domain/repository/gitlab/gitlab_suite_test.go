@@ -0,0 +1,19 @@
+/*
+
+Package gitlab_test is used to test the functionality
+within the gitlab package.
+
+*/
+package gitlab_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGitlab(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gitlab Suite")
+}
@@ -0,0 +1,237 @@
+/*
+
+Package local implements a way to detect the
+build tools of a repository checked out on
+the local filesystem, such as the workspace
+of a build pod, without any request to a git
+service or to the auth service.
+
+*/
+package local
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	"github.com/fabric8-services/build-tool-detector/log"
+)
+
+const (
+	gitDir           = ".git"
+	headFile         = "HEAD"
+	packedRefsFile   = "packed-refs"
+	symbolicRef      = "ref: "
+	branchPrefix     = "refs/heads/"
+	directoryField   = "directory"
+	shaLength        = 40
+	packedRefsPeeled = "^"
+)
+
+var (
+	// ErrNotDirectory the path of the
+	// repository is not a directory.
+	ErrNotDirectory = errors.New("not a directory")
+
+	// ErrSymlink the file is a symbolic link,
+	// which may point outside of the directory.
+	ErrSymlink = errors.New("symbolic links are not followed")
+
+	// skippedDirs are the directories which are not
+	// listed, as they are never pushed to a git
+	// service. Other dependency directories are
+	// listed as by the git services.
+	skippedDirs = map[string]bool{
+		gitDir:         true,
+		"node_modules": true,
+	}
+)
+
+// localRepository contains the values
+// pertaining to a local directory.
+type localRepository struct {
+	dir        string
+	path       string
+	precedence []string
+}
+
+// Create instantiate the repository checked out at
+// dir. The build tools are detected in path, relative
+// to dir, or in dir itself if path is empty. Missing
+// directories result in ErrResourceNotFound.
+func Create(dir string, path string, configuration config.Configuration) (types.RepositoryService, error) {
+	absolute, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absolute)
+	if os.IsNotExist(err) {
+		return nil, types.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, ErrNotDirectory
+	}
+
+	return localRepository{
+		dir:        absolute,
+		path:       filepath.ToSlash(path),
+		precedence: configuration.GetDetectorPrecedence(),
+	}, nil
+}
+
+// DetectBuildTool lists the directory and returns the
+// detected build tools, pinned by the override file of
+// the repository if any. The build tool type is set to
+// Unknown in case of an error.
+func (l localRepository) DetectBuildTool(ctx context.Context) (*types.Detection, error) {
+	return types.DetectIn(ctx, l, l.precedence)
+}
+
+// Contents returns the files of the directory, rooted
+// at the requested path, along with the revision
+// checked out, if the directory is a git repository.
+func (l localRepository) Contents(ctx context.Context) (*types.Tree, types.Revision, error) {
+	files, err := getContents(l.dir)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	files, err = files.Sub(l.path)
+	if err != nil {
+		return nil, types.Revision{}, err
+	}
+
+	return files, getRevision(l.dir), nil
+}
+
+// Owner returns no owner, local
+// directories have none.
+func (l localRepository) Owner() string {
+	return ""
+}
+
+// Repository returns the name
+// of the directory.
+func (l localRepository) Repository() string {
+	return filepath.Base(l.dir)
+}
+
+// Branch returns the branch checked
+// out, empty if there is none.
+func (l localRepository) Branch() string {
+	return getRevision(l.dir).Branch
+}
+
+// getContents lists every file of the directory, the
+// git directory, or the git file of worktrees and
+// submodules, and the dependency and build output
+// directories aside. Unreadable subdirectories are
+// skipped. Symbolic links are listed as files, and
+// their contents are not read.
+func getContents(dir string) (*types.Tree, error) {
+	var listing []types.Entry
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil && os.IsPermission(err) && file != dir {
+			log.Logger().WithError(err).WithField(directoryField, file).Warnf("unreadable entry skipped")
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || rel == "." {
+			return err
+		}
+		if info.IsDir() && skippedDirs[info.Name()] {
+			return filepath.SkipDir
+		}
+		if info.Name() == gitDir {
+			return nil
+		}
+		listing = append(listing, types.Entry{Path: filepath.ToSlash(rel), Dir: info.IsDir()})
+		return nil
+	})
+	if err != nil {
+		return nil, types.ErrFailedContentRetrieval
+	}
+
+	fetch := func(ctx context.Context, path string) ([]byte, error) {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		info, err := os.Lstat(file)
+		if os.IsNotExist(err) {
+			return nil, types.ErrFileNotFound
+		}
+		if err != nil {
+			return nil, types.ErrFailedContentRetrieval
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil, ErrSymlink
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, types.ErrFailedContentRetrieval
+		}
+		return content, nil
+	}
+	return types.NewTree(listing, fetch), nil
+}
+
+// getRevision returns the branch and the commit
+// checked out, read from the git directory. The
+// revision is empty outside of a git repository.
+func getRevision(dir string) types.Revision {
+	head, err := ioutil.ReadFile(filepath.Join(dir, gitDir, headFile))
+	if err != nil {
+		return types.Revision{}
+	}
+
+	ref := strings.TrimSpace(string(head))
+	if !strings.HasPrefix(ref, symbolicRef) {
+		return types.Revision{Commit: ref}
+	}
+	ref = strings.TrimPrefix(ref, symbolicRef)
+	return types.Revision{
+		Branch: strings.TrimPrefix(ref, branchPrefix),
+		Commit: resolveRef(dir, ref),
+	}
+}
+
+// resolveRef returns the commit the ref points at,
+// from its loose ref or else from the packed refs.
+// It is empty for branches without commits.
+func resolveRef(dir string, ref string) string {
+	loose, err := ioutil.ReadFile(filepath.Join(dir, gitDir, filepath.FromSlash(ref)))
+	if err == nil {
+		return strings.TrimSpace(string(loose))
+	}
+
+	packed, err := os.Open(filepath.Join(dir, gitDir, packedRefsFile))
+	if err != nil {
+		return ""
+	}
+	defer packed.Close()
+
+	scanner := bufio.NewScanner(packed)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, packedRefsPeeled) || len(line) <= shaLength {
+			continue
+		}
+		if strings.TrimSpace(line[shaLength:]) == ref {
+			return line[:shaLength]
+		}
+	}
+	return ""
+}
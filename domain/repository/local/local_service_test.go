@@ -0,0 +1,151 @@
+/*
+
+Package local_test is used to test the functionality
+within the local package.
+
+*/
+package local_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/local"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LocalService", func() {
+	ctx := context.TODO()
+	var dir string
+
+	writeFiles := func(files map[string]string) {
+		for file, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(file))
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).Should(BeNil())
+			Expect(ioutil.WriteFile(path, []byte(content), 0644)).Should(BeNil())
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "local")
+		Expect(err).Should(BeNil())
+	})
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Checked out branch", func() {
+		writeFiles(map[string]string{
+			"pom.xml":                       "<project><artifactId>app</artifactId></project>",
+			"frontend/package.json":         "{}",
+			".git/HEAD":                     "ref: refs/heads/feature/local\n",
+			".git/refs/heads/feature/local": "a2eb145933e1044956aa96fac4945be37970ed19\n",
+			".git/pom.xml":                  "<project/>",
+		})
+		repositoryService, err := local.Create(dir, "", *config.New())
+		Expect(err).Should(BeNil())
+		Expect(repositoryService.Repository()).Should(Equal(filepath.Base(dir)), "repository should be the directory")
+		Expect(repositoryService.Branch()).Should(Equal("feature/local"), "branch should be checked out")
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.BuildType).Should(Equal(types.Maven), "build tool type should be maven")
+		Expect(detection.Top().Maven.ArtifactID).Should(Equal("app"), "pom.xml should be read")
+		Expect(detection.Commit).Should(Equal("a2eb145933e1044956aa96fac4945be37970ed19"), "commit should be resolved")
+		Expect(detection.Matches).Should(HaveLen(1), "the git directory should not be listed")
+	})
+
+	It("Packed branch and path", func() {
+		writeFiles(map[string]string{
+			"pom.xml":               "<project><artifactId>app</artifactId></project>",
+			"frontend/package.json": `{"packageManager": "yarn@1.22.0"}`,
+			".git/HEAD":             "ref: refs/heads/master\n",
+			".git/packed-refs":      "# pack-refs with: peeled fully-peeled sorted\ncd7a01bc85da4d639239e143771bdab76a64c0b0 refs/heads/master\n^a2eb145933e1044956aa96fac4945be37970ed19\n",
+		})
+		repositoryService, err := local.Create(dir, "frontend", *config.New())
+		Expect(err).Should(BeNil())
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.BuildType).Should(Equal(types.NodeJS), "build tool type should be nodejs")
+		Expect(detection.Path).Should(Equal("frontend"), "path should be detected")
+		Expect(detection.Top().NodeJS.PackageManager).Should(Equal(types.Yarn), "package.json should be read")
+		Expect(detection.Revision).Should(Equal(types.Revision{Branch: "master", Commit: "cd7a01bc85da4d639239e143771bdab76a64c0b0"}))
+	})
+
+	It("Dependency directories and symbolic links", func() {
+		writeFiles(map[string]string{
+			"node_modules/left-pad/pom.xml":       "<project/>",
+			"vendor/github.com/pkg/errors/go.mod": "module github.com/pkg/errors\n",
+			"target/classes/build.gradle":         "",
+		})
+		outside, err := ioutil.TempDir("", "outside")
+		Expect(err).Should(BeNil())
+		defer os.RemoveAll(outside)
+		Expect(ioutil.WriteFile(filepath.Join(outside, "package.json"), []byte(`{"packageManager": "yarn@1.22.0"}`), 0644)).Should(BeNil())
+		Expect(os.Symlink(filepath.Join(outside, "package.json"), filepath.Join(dir, "package.json"))).Should(BeNil())
+
+		repositoryService, err := local.Create(dir, "", *config.New())
+		Expect(err).Should(BeNil())
+
+		files, _, err := repositoryService.Contents(ctx)
+		Expect(err).Should(BeNil())
+		Expect(files.Paths()).ShouldNot(ContainElement("node_modules/left-pad/pom.xml"), "node_modules should not be listed")
+		Expect(files.Paths()).Should(ContainElement("vendor/github.com/pkg/errors/go.mod"), "vendor should be listed as by the git services")
+		Expect(files.Paths()).Should(ContainElement("target/classes/build.gradle"), "target should be listed as by the git services")
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.Matches).Should(HaveLen(1), "only root marker files should be matched")
+		Expect(detection.BuildType).Should(Equal(types.NodeJS), "symbolic links should be listed")
+		if nodejs := detection.Top().NodeJS; nodejs != nil {
+			Expect(nodejs.PackageManager).ShouldNot(Equal(types.Yarn), "symbolic links should not be read")
+		}
+	})
+
+	It("Unreadable directory", func() {
+		if os.Geteuid() == 0 {
+			Skip("permissions are not enforced for root")
+		}
+		writeFiles(map[string]string{"pom.xml": "<project/>", "secret/build.gradle": ""})
+		Expect(os.Chmod(filepath.Join(dir, "secret"), 0)).Should(BeNil())
+		defer os.Chmod(filepath.Join(dir, "secret"), 0755)
+
+		repositoryService, err := local.Create(dir, "", *config.New())
+		Expect(err).Should(BeNil())
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(BeNil())
+		Expect(detection.BuildType).Should(Equal(types.Maven), "unreadable directories should be skipped")
+	})
+
+	It("Not a git repository - unknown", func() {
+		writeFiles(map[string]string{"README.md": "# app"})
+		repositoryService, err := local.Create(dir, "", *config.New())
+		Expect(err).Should(BeNil())
+
+		detection, err := repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(Equal(types.ErrFailedContentRetrieval))
+		Expect(detection.BuildType).Should(Equal(types.Unknown), "build tool type should be unknown")
+		Expect(detection.Revision).Should(Equal(types.Revision{}), "revision should be empty")
+	})
+
+	It("Path not found", func() {
+		repositoryService, err := local.Create(dir, "backend", *config.New())
+		Expect(err).Should(BeNil())
+
+		_, err = repositoryService.DetectBuildTool(ctx)
+		Expect(err).Should(Equal(types.ErrResourceNotFound))
+	})
+
+	It("Directory not found", func() {
+		_, err := local.Create(filepath.Join(dir, "missing"), "", *config.New())
+		Expect(err).Should(Equal(types.ErrResourceNotFound))
+	})
+})
@@ -0,0 +1,19 @@
+/*
+
+Package local_test is used to test the functionality
+within the local package.
+
+*/
+package local_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLocal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Local Suite")
+}
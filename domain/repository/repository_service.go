@@ -1,25 +1,26 @@
 /*
-
 Package repository handles detecting build tool types
 for git services such as github, bitbucket
 and gitlab.
 
-Currently the build-tool-detector only
-supports github and can only recognize
-maven.
-
+Github and the configured github enterprise
+instances, gitlab.com and the configured
+self-hosted gitlab instances, bitbucket
+cloud and the configured bitbucket server
+instances are supported.
 */
 package repository
 
 import (
 	"context"
-	"net/url"
-	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/giturl"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/bitbucket"
 	"github.com/fabric8-services/build-tool-detector/domain/repository/github"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/gitlab"
 	"github.com/fabric8-services/build-tool-detector/domain/token"
 	"github.com/fabric8-services/build-tool-detector/domain/types"
 )
@@ -30,35 +31,70 @@ var (
 )
 
 const (
-	slash      = "/"
-	githubHost = "github.com"
+	githubHost    = "github.com"
+	bitbucketHost = "bitbucket.org"
 )
 
-// CreateService performs a simple url parse and split
-// in order to retrieve the owner, repository
-// and potentially the branch.
+// CreateService parses the url, web or clone url, in
+// order to retrieve the owner, repository and
+// potentially the branch or ref. The optional ref,
+// a branch, tag, commit sha or pull request ref such
+// as refs/pull/123/head, takes precedence over the
+// optional branch, both take precedence over the
+// ref of the url. The ref of /tree/<ref> urls is
+// resolved as a tag or commit if no branch matches.
+// The optional path, the directory the build tools
+// are detected in, takes precedence over the
+// subpath of the url.
 //
-// Note: This method will likely need to be enhanced
-// to handle different github url formats.
-func CreateService(ctx *context.Context, urlToParse string, branch *string, configuration config.Configuration) (types.RepositoryService, error) {
+// The git service is selected by the host, github
+// enterprise, gitlab and bitbucket server hosts are
+// configured through GetGitHubEnterpriseURLs,
+// GetGitLabHosts and GetBitbucketServerHosts.
+func CreateService(ctx *context.Context, urlToParse string, branch *string, ref *string, path *string, configuration config.Configuration) (types.RepositoryService, error) {
 
-	u, err := url.Parse(urlToParse)
-
-	// Fail on error or empty host or empty scheme.
-	if err != nil || u.Host == "" || u.Scheme == "" {
+	// Fail on error or empty host.
+	location, err := giturl.Parse(urlToParse)
+	if err != nil {
 		return nil, github.ErrInvalidPath
 	}
 
-	// Currently only support Github.
-	if u.Host != githubHost {
-		return nil, ErrUnsupportedService
+	switch host := location.Host; {
+	case host == githubHost, isGitHubEnterpriseHost(configuration, host):
+		return createGithubService(ctx, withQuery(location, branch, ref, path), configuration)
+	case configuration.IsGitLabHost(host):
+		return createGitlabService(ctx, withQuery(location.Nested(), branch, ref, path), configuration)
+	case host == bitbucketHost:
+		return createBitbucketService(ctx, withQuery(location, branch, ref, path), configuration, bitbucket.CreateCloud)
+	case configuration.IsBitbucketServerHost(host):
+		return createBitbucketService(ctx, withQuery(location.Server(), branch, ref, path), configuration, bitbucket.CreateServer)
 	}
+	return nil, ErrUnsupportedService
+}
 
-	urlSegments := strings.Split(u.Path, slash)
-	if len(urlSegments) < 3 {
+// withQuery applies the optional ref, branch
+// and path query parameters to the url.
+func withQuery(location *giturl.URL, branch *string, ref *string, path *string) *giturl.URL {
+	switch {
+	case ref != nil && *ref != "":
+		location = location.WithRef(*ref)
+	case branch != nil && *branch != "":
+		location = location.WithBranch(*branch)
+	}
+	if path != nil && *path != "" {
+		location = location.WithPath(*path)
+	}
+	return location
+}
+
+// createGithubService retrieves the github
+// token and creates the github service.
+func createGithubService(ctx *context.Context, location *giturl.URL, configuration config.Configuration) (types.RepositoryService, error) {
+	if location.Owner == "" || location.Repository == "" {
 		return nil, github.ErrUnsupportedGithubURL
 	}
 
+	u := location.WebURL()
 	tk, err := token.GetGitHubToken(ctx, configuration.GetAuthServiceURL(), u)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve token from auth")
@@ -66,5 +102,41 @@ func CreateService(ctx *context.Context, urlToParse string, branch *string, conf
 	if tk == nil {
 		return nil, errors.Wrap(err, "failed to retrieve token from auth")
 	}
-	return github.Create(urlSegments, branch, configuration, *tk)
+	if baseURL, ok := configuration.GetGitHubEnterpriseURL(u.Host); ok {
+		return github.CreateEnterprise(baseURL, location, configuration, *tk)
+	}
+	return github.Create(location, configuration, *tk)
+}
+
+// isGitHubEnterpriseHost returns whether
+// the host is a github enterprise instance.
+func isGitHubEnterpriseHost(configuration config.Configuration, host string) bool {
+	_, ok := configuration.GetGitHubEnterpriseURL(host)
+	return ok
+}
+
+// createGitlabService retrieves the gitlab
+// token and creates the gitlab service.
+func createGitlabService(ctx *context.Context, location *giturl.URL, configuration config.Configuration) (types.RepositoryService, error) {
+	tk, err := token.GetGitLabToken(ctx, configuration.GetAuthServiceURL(), location.WebURL())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve token from auth")
+	}
+	if tk == nil {
+		return nil, errors.New("failed to retrieve token from auth")
+	}
+	return gitlab.Create(location, configuration, *tk)
+}
+
+// createBitbucketService retrieves the bitbucket token
+// and creates the bitbucket cloud or server service.
+func createBitbucketService(ctx *context.Context, location *giturl.URL, configuration config.Configuration, create func(*giturl.URL, config.Configuration, string) (types.RepositoryService, error)) (types.RepositoryService, error) {
+	tk, err := token.GetBitbucketToken(ctx, configuration.GetAuthServiceURL(), location.WebURL())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve token from auth")
+	}
+	if tk == nil {
+		return nil, errors.New("failed to retrieve token from auth")
+	}
+	return create(location, configuration, *tk)
 }
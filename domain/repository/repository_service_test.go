@@ -26,25 +26,31 @@ var _ = Describe("GitServiceType", func() {
 	})
 	Context("CreateService", func() {
 		It("Faulty Host - empty", func() {
-			serviceType, err := repository.CreateService(&ctx, "", nil, *configuration)
+			serviceType, err := repository.CreateService(&ctx, "", nil, nil, nil, *configuration)
 			Expect(serviceType).Should(BeNil(), "service type should be 'nil'")
 			Expect(err.Error()).Should(BeEquivalentTo(github.ErrInvalidPath.Error()), "service type should be '400'")
 		})
 
 		It("Faulty Host - non-existent", func() {
-			serviceType, err := repository.CreateService(&ctx, "test/test", nil, *configuration)
+			serviceType, err := repository.CreateService(&ctx, "test/test", nil, nil, nil, *configuration)
 			Expect(serviceType).Should(BeNil(), "service type should be 'nil'")
 			Expect(err.Error()).Should(BeEquivalentTo(github.ErrInvalidPath.Error()), "service type should be '400'")
 		})
 
 		It("Faulty Host - not github.com", func() {
-			serviceType, err := repository.CreateService(&ctx, "http://test.com/test/test", nil, *configuration)
+			serviceType, err := repository.CreateService(&ctx, "http://test.com/test/test", nil, nil, nil, *configuration)
+			Expect(serviceType).Should(BeNil(), "service type should be 'nil'")
+			Expect(err.Error()).Should(BeEquivalentTo(repository.ErrUnsupportedService.Error()), "service type should be '500'")
+		})
+
+		It("Faulty Host - clone url not github.com", func() {
+			serviceType, err := repository.CreateService(&ctx, "git@test.com:test/test.git", nil, nil, nil, *configuration)
 			Expect(serviceType).Should(BeNil(), "service type should be 'nil'")
 			Expect(err.Error()).Should(BeEquivalentTo(repository.ErrUnsupportedService.Error()), "service type should be '500'")
 		})
 
 		It("Faulty url - no repository", func() {
-			serviceType, err := repository.CreateService(&ctx, "http://github.com/test", nil, *configuration)
+			serviceType, err := repository.CreateService(&ctx, "http://github.com/test", nil, nil, nil, *configuration)
 			Expect(serviceType).Should(BeNil(), "service type should be 'nil'")
 			Expect(err.Error()).Should(BeEquivalentTo(github.ErrUnsupportedGithubURL.Error()), "service type should be '400'")
 		})
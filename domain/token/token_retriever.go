@@ -65,6 +65,22 @@ func tokenForService(ctx *context.Context, authClient *client.Client, forService
 
 // GetGitHubToken retrieve GitHub token associated to given openshift.io token using auth service.
 func GetGitHubToken(ctx *context.Context, authServiceURL string, u *url.URL) (*string, error) {
+	return getToken(ctx, authServiceURL, u)
+}
+
+// GetGitLabToken retrieve GitLab token associated to given openshift.io token using auth service.
+func GetGitLabToken(ctx *context.Context, authServiceURL string, u *url.URL) (*string, error) {
+	return getToken(ctx, authServiceURL, u)
+}
+
+// GetBitbucketToken retrieve Bitbucket token associated to given openshift.io token using auth service.
+func GetBitbucketToken(ctx *context.Context, authServiceURL string, u *url.URL) (*string, error) {
+	return getToken(ctx, authServiceURL, u)
+}
+
+// getToken retrieve the token of the git service
+// hosting the url using auth service.
+func getToken(ctx *context.Context, authServiceURL string, u *url.URL) (*string, error) {
 	url, err := url.Parse(authServiceURL)
 	if err != nil {
 		return nil, errors.Wrap(err, "auth service url not found")
@@ -42,6 +42,18 @@ var _ = Describe("GetGitHubToken", func() {
 			tr, _ := token.GetGitHubToken(&ctx, authURL, u)
 			Expect(*tr).Should(Equal("ACCESS_TOKEN"), "gh token should match the auth service retirved token")
 		})
+
+		It("Status OK - returns the gitlab token", func() {
+			gitlabURL, _ := url.Parse("https://gitlab.com")
+			tr, _ := token.GetGitLabToken(&ctx, authURL, gitlabURL)
+			Expect(*tr).Should(Equal("ACCESS_TOKEN"), "gitlab token should match the auth service retirved token")
+		})
+
+		It("Status OK - returns the bitbucket token", func() {
+			bitbucketURL, _ := url.Parse("https://bitbucket.org")
+			tr, _ := token.GetBitbucketToken(&ctx, authURL, bitbucketURL)
+			Expect(*tr).Should(Equal("ACCESS_TOKEN"), "bitbucket token should match the auth service retirved token")
+		})
 	})
 	Context("Error Status", func() {
 		authURL := "https://auth.prod-preview.openshift.io"
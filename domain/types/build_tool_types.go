@@ -4,6 +4,11 @@ Package types implements a simple way to
 create a GoaBuildToolDetector struct with
 the recognized build type.
 
+Build tools are recognized by Detectors
+kept in a registry. Additional build tools
+can be supported by calling Register with
+a Detector listing their marker files.
+
 The GoaBuildToolDetector struct will result
 in the following JSON response:
 
@@ -24,6 +29,9 @@ in the following JSON response:
 package types
 
 import (
+	"math"
+	"sort"
+
 	"github.com/fabric8-services/build-tool-detector/app"
 )
 
@@ -44,21 +52,19 @@ const (
 	Unknown = "unknown"
 )
 
-// BuildType TODO
+const (
+	mavenPriority  = 300
+	nodeJSPriority = 200
+	golangPriority = 100
+)
+
+// BuildType associates a build
+// tool type with a marker file.
 type BuildType struct {
 	BuildType string
 	File      string
 }
 
-// NewMaven will create a buildToolDetector
-// struct with the BuildToolType set
-// to maven.
-func NewMaven() *app.GoaBuildToolDetector {
-	return &app.GoaBuildToolDetector{
-		BuildToolType: Maven,
-	}
-}
-
 // NewNodeJS will create a buildToolDetector
 // struct with the BuildToolType set
 // to NodeJS.
@@ -86,29 +92,54 @@ func NewUnknown() *app.GoaBuildToolDetector {
 	}
 }
 
-// GetTypes returns the BuildType for all
-// supported build tools.
+// GetTypes returns a BuildType for every marker
+// file of the registered detectors, ordered
+// by descending detector priority.
 func GetTypes() []BuildType {
-	buildTypes := make([]BuildType, 3)
-
-	buildTypes[0] = getTypeMaven()
-	buildTypes[1] = getTypeNodeJS()
-	buildTypes[2] = getTypeGolang()
-
+	var buildTypes []BuildType
+	for _, detector := range Detectors() {
+		for _, file := range detector.Files() {
+			buildTypes = append(buildTypes, BuildType{detector.Name(), file})
+		}
+	}
 	return buildTypes
 }
 
-// getTypeMaven returns BuildType for maven.
-func getTypeMaven() BuildType {
-	return BuildType{Maven, pomXML}
+// GetTypesByPrecedence returns the BuildType for all
+// supported build tools ordered by precedence. Build
+// tool types listed in preferred come first, in the
+// given order, followed by the remaining ones in
+// descending detector priority.
+func GetTypesByPrecedence(preferred []string) []BuildType {
+	rank := make(map[string]int, len(preferred))
+	for i, buildToolType := range preferred {
+		if _, ok := rank[buildToolType]; !ok {
+			rank[buildToolType] = i
+		}
+	}
+
+	buildTypes := GetTypes()
+	sort.SliceStable(buildTypes, func(i, j int) bool {
+		return rankOf(rank, buildTypes[i].BuildType) < rankOf(rank, buildTypes[j].BuildType)
+	})
+	return buildTypes
 }
 
-// getTypeNodeJS returns BuildType for nodejs.
-func getTypeNodeJS() BuildType {
-	return BuildType{NodeJS, packageJSON}
+// rankOf returns the position of the build tool
+// type within the preferred build tool types. Build
+// tool types which are not preferred rank last.
+func rankOf(rank map[string]int, buildToolType string) int {
+	if i, ok := rank[buildToolType]; ok {
+		return i
+	}
+	return math.MaxInt32
 }
 
-// getTypeGolang returns BuildType for golang.
-func getTypeGolang() BuildType {
-	return BuildType{Golang, mainFile}
+// init registers the built-in detectors.
+func init() {
+	mustRegister(getTypeMaven())
+	mustRegister(getTypeGradle())
+	mustRegister(getTypeNodeJS())
+	mustRegister(getTypePython())
+	mustRegister(getTypeGolang())
 }
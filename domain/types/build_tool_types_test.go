@@ -30,19 +30,64 @@ var _ = Describe("BuildToolType", func() {
 
 	Context("GetTypes", func() {
 		It("Get Types", func() {
-			nodejs := NewNodeJS()
 			maven := NewMaven()
-			golang := NewGolang()
 			types := GetTypes()
 
 			Expect(types[0].BuildType).Should(BeEquivalentTo(maven.BuildToolType), "build tool type should be 'maven'")
 			Expect(types[0].File).Should(BeEquivalentTo("pom.xml"), "file name should be 'pom.xml'")
 
-			Expect(types[1].BuildType).Should(BeEquivalentTo(nodejs.BuildToolType), "build tool type should be 'nodejs'")
-			Expect(types[1].File).Should(BeEquivalentTo("package.json"), "file name should be 'package.json'")
+			for _, file := range []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts", "gradlew"} {
+				Expect(types).Should(ContainElement(BuildType{BuildType: Gradle, File: file}), "'%s' should identify gradle", file)
+			}
 
-			Expect(types[2].BuildType).Should(BeEquivalentTo(golang.BuildToolType), "build tool type should be 'golang'")
-			Expect(types[2].File).Should(BeEquivalentTo("main.go"), "file name should be 'main.go'")
+			for _, file := range []string{"package.json", "pnpm-lock.yaml", "yarn.lock", "package-lock.json", "npm-shrinkwrap.json", "tsconfig.json"} {
+				Expect(types).Should(ContainElement(BuildType{BuildType: NodeJS, File: file}), "'%s' should identify nodejs", file)
+			}
+
+			for _, file := range []string{"lerna.json", "nx.json", "turbo.json", "pnpm-workspace.yaml"} {
+				Expect(types).Should(ContainElement(BuildType{BuildType: NodeJS, File: file}), "'%s' should identify nodejs", file)
+			}
+
+			for _, file := range []string{"pyproject.toml", "setup.py", "setup.cfg", "Pipfile", "requirements.txt"} {
+				Expect(types).Should(ContainElement(BuildType{BuildType: Python, File: file}), "'%s' should identify python", file)
+			}
+
+			for _, file := range []string{"go.mod", "Gopkg.toml", "glide.yaml", "main.go"} {
+				Expect(types).Should(ContainElement(BuildType{BuildType: Golang, File: file}), "'%s' should identify golang", file)
+			}
+		})
+	})
+
+	Context("GetTypesByPrecedence", func() {
+		It("Get Types - no preference", func() {
+			Expect(GetTypesByPrecedence(nil)).Should(Equal(GetTypes()), "types should be ordered by priority")
+		})
+
+		It("Get Types - preferred build tool types first", func() {
+			var order []string
+			for _, buildType := range GetTypesByPrecedence([]string{"golang", "nodejs"}) {
+				if len(order) == 0 || order[len(order)-1] != buildType.BuildType {
+					order = append(order, buildType.BuildType)
+				}
+			}
+
+			Expect(order[0]).Should(BeEquivalentTo("golang"), "build tool type should be 'golang'")
+			Expect(order[1]).Should(BeEquivalentTo("nodejs"), "build tool type should be 'nodejs'")
+			Expect(order[2]).Should(BeEquivalentTo("maven"), "build tool type should be 'maven'")
+			Expect(order[3]).Should(BeEquivalentTo("gradle"), "build tool type should be 'gradle'")
+		})
+
+		It("Get Types - duplicate preferred build tool type", func() {
+			var order []string
+			for _, buildType := range GetTypesByPrecedence([]string{"golang", "golang", "nodejs"}) {
+				if len(order) == 0 || order[len(order)-1] != buildType.BuildType {
+					order = append(order, buildType.BuildType)
+				}
+			}
+
+			Expect(order[0]).Should(BeEquivalentTo("golang"), "build tool type should be 'golang'")
+			Expect(order[1]).Should(BeEquivalentTo("nodejs"), "duplicates should not rank nodejs with the remaining types")
+			Expect(order[2]).Should(BeEquivalentTo("maven"), "build tool type should be 'maven'")
 		})
 	})
 })
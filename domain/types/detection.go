@@ -0,0 +1,194 @@
+package types
+
+import (
+	"context"
+)
+
+const (
+	fullConfidence = 1.0
+)
+
+// Match is a build tool detected
+// within a repository.
+type Match struct {
+	BuildType  string
+	Confidence float64
+	Evidence   string
+	Files      []string
+	Runtime    *Runtime
+	Framework  *Framework
+	Maven      *MavenBuild
+	Gradle     *GradleBuild
+	NodeJS     *NodeJSBuild
+	Python     *PythonBuild
+	Golang     *GolangBuild
+}
+
+// Revision is the revision of a repository the
+// build tools are detected at. Branch is set when
+// detecting on a branch, Ref when detecting at a
+// tag, commit or pull request, and Commit is the
+// sha of the commit resolved.
+type Revision struct {
+	Branch string
+	Ref    string
+	Commit string
+}
+
+// Detection holds the build tools detected within a
+// repository, ordered by precedence. BuildType is the
+// build tool with the highest precedence. Path is the
+// directory the build tools are detected in, empty
+// for the root of the repository. Overridden is set
+// when the results are pinned by the repository.
+type Detection struct {
+	Revision
+	Path         string
+	BuildType    string
+	Matches      []Match
+	Overridden   bool
+	BuilderImage string
+}
+
+// NewDetection creates a Detection from the marker
+// files found within a repository, which must be
+// ordered by precedence. Each build tool results
+// in a single Match using the marker file with the
+// highest confidence as evidence.
+func NewDetection(found []BuildType) *Detection {
+	detection := Detection{
+		BuildType: Unknown,
+	}
+
+	matches := make(map[string]int)
+	for _, buildType := range found {
+		confidence := confidenceOf(buildType)
+		i, ok := matches[buildType.BuildType]
+		if !ok {
+			matches[buildType.BuildType] = len(detection.Matches)
+			detection.Matches = append(detection.Matches, Match{
+				BuildType:  buildType.BuildType,
+				Confidence: confidence,
+				Evidence:   buildType.File,
+				Files:      []string{buildType.File},
+			})
+			continue
+		}
+		detection.Matches[i].Files = append(detection.Matches[i].Files, buildType.File)
+		if confidence > detection.Matches[i].Confidence {
+			detection.Matches[i].Confidence = confidence
+			detection.Matches[i].Evidence = buildType.File
+		}
+	}
+
+	if len(detection.Matches) > 0 {
+		detection.BuildType = detection.Matches[0].BuildType
+	}
+	return &detection
+}
+
+// Detect evaluates the marker files of the registered
+// detectors, in order of precedence, against the files
+// of the tree and describes the matches. Marker files
+// may be patterns, and must satisfy the predicates of
+// detectors implementing Verifier. The detection is
+// returned along with the first verify or describe
+// error. The files probed and the precedence decision
+// are recorded in the explanation of the context.
+func Detect(ctx context.Context, tree *Tree, precedence []string) (*Detection, error) {
+	var found []BuildType
+	var err error
+	explanation := ExplanationFrom(ctx)
+	seen := make(map[BuildType]bool)
+	for _, buildType := range GetTypesByPrecedence(precedence) {
+		files := tree.Glob(buildType.File)
+		if len(files) == 0 {
+			explanation.probe(buildType, FileProbe{Pattern: buildType.File, Result: ProbeMissing})
+		}
+		for _, file := range files {
+			marker := BuildType{BuildType: buildType.BuildType, File: file}
+			if seen[marker] {
+				continue
+			}
+			seen[marker] = true
+			probe := FileProbe{Pattern: buildType.File, File: file, Result: ProbeMatched}
+			ok, verifyErr := verify(ctx, tree, marker)
+			if verifyErr != nil && err == nil {
+				err = verifyErr
+			}
+			switch {
+			case verifyErr != nil:
+				probe.Result, probe.Error = ProbeFailed, verifyErr.Error()
+			case ok:
+				found = append(found, marker)
+			default:
+				probe.Result = ProbeRejected
+			}
+			explanation.probe(buildType, probe)
+		}
+	}
+
+	detection := NewDetection(found)
+	detection.Path = tree.Dir()
+	explanation.decide(detection, precedence)
+	if describeErr := detection.Describe(ctx, tree); err == nil {
+		err = describeErr
+	}
+	return detection, err
+}
+
+// Describe lets the detectors implementing Describer
+// add details to their match. All matches are
+// described, the first error is returned.
+func (d *Detection) Describe(ctx context.Context, src Source) error {
+	var err error
+	for i := range d.Matches {
+		detector, ok := Lookup(d.Matches[i].BuildType)
+		if !ok {
+			continue
+		}
+		describer, ok := detector.(Describer)
+		if !ok {
+			continue
+		}
+		if describeErr := describer.Describe(ctx, src, &d.Matches[i]); describeErr != nil && err == nil {
+			err = describeErr
+		}
+	}
+	return err
+}
+
+// Top returns the match with the highest
+// precedence, or nil if nothing matched.
+func (d *Detection) Top() *Match {
+	if len(d.Matches) == 0 {
+		return nil
+	}
+	return &d.Matches[0]
+}
+
+// confidenceOf returns the confidence with which
+// the marker file identifies the build tool.
+func confidenceOf(buildType BuildType) float64 {
+	detector, ok := Lookup(buildType.BuildType)
+	if !ok {
+		return fullConfidence
+	}
+	if weigher, ok := detector.(Weigher); ok {
+		return weigher.Confidence(buildType.File)
+	}
+	return fullConfidence
+}
+
+// verify returns whether the marker file satisfies
+// the predicates of the detector, if any.
+func verify(ctx context.Context, src Source, buildType BuildType) (bool, error) {
+	detector, ok := Lookup(buildType.BuildType)
+	if !ok {
+		return true, nil
+	}
+	if verifier, ok := detector.(Verifier); ok {
+		return verifier.Verify(ctx, src, buildType.File)
+	}
+	return true, nil
+}
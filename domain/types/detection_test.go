@@ -0,0 +1,61 @@
+package types_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/fabric8-services/build-tool-detector/app"
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+// weightedDetector is a detector with a
+// weak secondary marker file.
+type weightedDetector struct {
+	Detector
+}
+
+func (w weightedDetector) Confidence(file string) float64 {
+	if file == "weighted.lock" {
+		return 0.5
+	}
+	return 1
+}
+
+var _ = Describe("Detection", func() {
+	var restore func()
+
+	BeforeEach(func() {
+		restore = SnapshotRegistry()
+	})
+	AfterEach(func() {
+		restore()
+	})
+
+	Context("NewDetection", func() {
+		It("No marker files found", func() {
+			detection := NewDetection(nil)
+			Expect(detection.BuildType).Should(BeEquivalentTo("unknown"), "build tool type should be 'unknown'")
+			Expect(detection.Matches).Should(BeEmpty(), "there should be no matches")
+		})
+
+		It("Several build tools found", func() {
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}, {BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.BuildType).Should(BeEquivalentTo("nodejs"), "build tool type should be 'nodejs'")
+			Expect(detection.Matches).Should(Equal([]Match{
+				{BuildType: NodeJS, Confidence: 1, Evidence: "package.json", Files: []string{"package.json"}},
+				{BuildType: Maven, Confidence: 1, Evidence: "pom.xml", Files: []string{"pom.xml"}},
+			}), "nodejs and maven should match")
+		})
+
+		It("Several marker files found for a build tool", func() {
+			constructor := func() *app.GoaBuildToolDetector { return &app.GoaBuildToolDetector{BuildToolType: "weighted"} }
+			err := Register(weightedDetector{NewDetector("weighted", 2, constructor, "weighted.lock", "weighted.build")})
+			Expect(err).Should(BeNil())
+
+			detection := NewDetection([]BuildType{{BuildType: "weighted", File: "weighted.lock"}, {BuildType: "weighted", File: "weighted.build"}})
+			Expect(detection.Matches).Should(Equal([]Match{
+				{BuildType: "weighted", Confidence: 1, Evidence: "weighted.build", Files: []string{"weighted.lock", "weighted.build"}},
+			}), "the marker file with the highest confidence should be the evidence")
+		})
+	})
+})
@@ -0,0 +1,217 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/fabric8-services/build-tool-detector/app"
+)
+
+var (
+	// ErrDuplicateDetector a detector is already
+	// registered for the build tool type.
+	ErrDuplicateDetector = errors.New("detector already registered")
+
+	// ErrInvalidDetector the detector has no name
+	// or no marker files.
+	ErrInvalidDetector = errors.New("detector is invalid")
+
+	// ErrFileNotFound the file or directory
+	// is not present in the repository.
+	ErrFileNotFound = errors.New("file not found")
+)
+
+// Detector describes how a build tool
+// is recognized within a repository.
+type Detector interface {
+	// Name returns the build tool type
+	// reported when the detector matches.
+	Name() string
+
+	// Files returns the marker files
+	// identifying the build tool.
+	Files() []string
+
+	// Priority orders the detectors, the
+	// detectors with the highest priority
+	// are evaluated first.
+	Priority() int
+
+	// New creates the response for
+	// the build tool.
+	New() *app.GoaBuildToolDetector
+}
+
+// Weigher is implemented by detectors whose marker
+// files identify the build tool with different
+// levels of confidence. Marker files of detectors
+// not implementing Weigher have full confidence.
+type Weigher interface {
+	// Confidence returns the confidence, between
+	// 0 and 1, with which the marker file
+	// identifies the build tool.
+	Confidence(file string) float64
+}
+
+// Describer is implemented by detectors able to
+// add details about the build tool to a match,
+// either from the marker files found or by
+// inspecting the repository contents.
+type Describer interface {
+	// Describe adds the build tool
+	// details to the match.
+	Describe(ctx context.Context, src Source, match *Match) error
+}
+
+// Verifier is implemented by detectors whose marker
+// files only identify the build tool when their
+// contents satisfy predicates.
+type Verifier interface {
+	// Verify returns whether the marker
+	// file identifies the build tool.
+	Verify(ctx context.Context, src Source, file string) (bool, error)
+}
+
+// Source provides read access to
+// the files of a repository.
+type Source interface {
+	// ReadFile returns the contents of the
+	// file at path, relative to the
+	// repository root.
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+
+	// ReadDir returns the entries of the
+	// directory at path, relative to the
+	// repository root.
+	ReadDir(ctx context.Context, path string) ([]Entry, error)
+}
+
+// Entry is a file or directory
+// within a repository.
+type Entry struct {
+	Path string
+	Dir  bool
+}
+
+// markerDetector is a Detector matching
+// on the presence of marker files.
+type markerDetector struct {
+	name        string
+	files       []string
+	priority    int
+	constructor func() *app.GoaBuildToolDetector
+}
+
+// registry holds the registered detectors
+// keyed by build tool type.
+var registry = struct {
+	sync.RWMutex
+	detectors map[string]Detector
+}{detectors: make(map[string]Detector)}
+
+// NewDetector creates a Detector recognizing the
+// build tool through the given marker files. If
+// constructor is nil, the response will only
+// contain the build tool type.
+func NewDetector(name string, priority int, constructor func() *app.GoaBuildToolDetector, files ...string) Detector {
+	if constructor == nil {
+		constructor = func() *app.GoaBuildToolDetector {
+			return &app.GoaBuildToolDetector{
+				BuildToolType: name,
+			}
+		}
+	}
+	return markerDetector{
+		name:        name,
+		files:       files,
+		priority:    priority,
+		constructor: constructor,
+	}
+}
+
+// Name returns the build tool type.
+func (m markerDetector) Name() string {
+	return m.name
+}
+
+// Files returns the marker files.
+func (m markerDetector) Files() []string {
+	return m.files
+}
+
+// Priority returns the detector priority.
+func (m markerDetector) Priority() int {
+	return m.priority
+}
+
+// New creates the response for the build tool.
+func (m markerDetector) New() *app.GoaBuildToolDetector {
+	return m.constructor()
+}
+
+// Register adds a detector to the registry. Build
+// tool types can only be registered once.
+func Register(detector Detector) error {
+	if detector == nil || detector.Name() == "" || len(detector.Files()) == 0 {
+		return ErrInvalidDetector
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.detectors[detector.Name()]; ok {
+		return ErrDuplicateDetector
+	}
+	registry.detectors[detector.Name()] = detector
+	return nil
+}
+
+// Lookup returns the detector registered
+// for the build tool type.
+func Lookup(buildToolType string) (Detector, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	detector, ok := registry.detectors[buildToolType]
+	return detector, ok
+}
+
+// Detectors returns all registered detectors ordered
+// by descending priority, ties are ordered by name.
+func Detectors() []Detector {
+	registry.RLock()
+	detectors := make([]Detector, 0, len(registry.detectors))
+	for _, detector := range registry.detectors {
+		detectors = append(detectors, detector)
+	}
+	registry.RUnlock()
+
+	sort.Slice(detectors, func(i, j int) bool {
+		if detectors[i].Priority() != detectors[j].Priority() {
+			return detectors[i].Priority() > detectors[j].Priority()
+		}
+		return detectors[i].Name() < detectors[j].Name()
+	})
+	return detectors
+}
+
+// New creates the response for the build tool
+// type. Unregistered build tool types result
+// in the unknown response.
+func New(buildToolType string) *app.GoaBuildToolDetector {
+	detector, ok := Lookup(buildToolType)
+	if !ok {
+		return NewUnknown()
+	}
+	return detector.New()
+}
+
+// mustRegister registers a built-in
+// detector and panics on failure.
+func mustRegister(detector Detector) {
+	if err := Register(detector); err != nil {
+		panic(err)
+	}
+}
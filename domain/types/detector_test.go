@@ -0,0 +1,54 @@
+package types_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Detector", func() {
+	var restore func()
+
+	BeforeEach(func() {
+		restore = SnapshotRegistry()
+	})
+	AfterEach(func() {
+		restore()
+	})
+
+	Context("Register", func() {
+		It("Register custom detector", func() {
+			err := Register(NewDetector("custom", 1, nil, "custom.build"))
+			Expect(err).Should(BeNil(), "registering a new build tool type should succeed")
+
+			detector, ok := Lookup("custom")
+			Expect(ok).Should(BeTrue(), "detector should be registered")
+			Expect(detector.Files()).Should(ConsistOf("custom.build"), "marker files should be 'custom.build'")
+			Expect(New("custom").BuildToolType).Should(BeEquivalentTo("custom"), "build tool type should be 'custom'")
+
+			types := GetTypes()
+			Expect(types[len(types)-1].BuildType).Should(BeEquivalentTo("custom"), "lowest priority detector should be last")
+		})
+
+		It("Register duplicate detector", func() {
+			err := Register(NewDetector(Maven, 1, nil, "pom.xml"))
+			Expect(err).Should(Equal(ErrDuplicateDetector), "registering maven twice should fail")
+		})
+
+		It("Register invalid detector", func() {
+			err := Register(NewDetector("nofiles", 1, nil))
+			Expect(err).Should(Equal(ErrInvalidDetector), "registering a detector without marker files should fail")
+		})
+	})
+
+	Context("New", func() {
+		It("New unregistered build tool type", func() {
+			Expect(New("unregistered").BuildToolType).Should(BeEquivalentTo("unknown"), "build tool type should be 'unknown'")
+		})
+
+		It("New registered build tool type", func() {
+			Expect(New(NodeJS).BuildToolType).Should(BeEquivalentTo("nodejs"), "build tool type should be 'nodejs'")
+		})
+	})
+})
@@ -0,0 +1,222 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// ProbeMatched the file identifies the build tool.
+	ProbeMatched = "matched"
+
+	// ProbeMissing no file of the repository
+	// matches the marker file pattern.
+	ProbeMissing = "missing"
+
+	// ProbeRejected the file does not satisfy
+	// the predicates of the detector.
+	ProbeRejected = "rejected"
+
+	// ProbeFailed the predicates of the detector
+	// could not be evaluated against the file.
+	ProbeFailed = "failed"
+)
+
+// explanationKey is the context key
+// of the explanation being collected.
+type explanationKey struct{}
+
+// Explanation records how a detection was reached: the
+// requests made to the git service, the marker files
+// probed for every registered build type and the
+// precedence decision taken. It is only collected
+// for contexts created by WithExplanation.
+type Explanation struct {
+	Requests []Request
+	Probes   []Probe
+	Override string
+	Decision string
+
+	mu     sync.Mutex
+	pinned string
+}
+
+// Request is a request made to the git service.
+// Status is the http status received, 0 when
+// no response was received.
+type Request struct {
+	Method string
+	URL    string
+	Status int
+	Error  string
+}
+
+// Probe holds the marker files probed for a build
+// type. Precedence is the position of the build type
+// in the order the detectors are evaluated, from 1.
+type Probe struct {
+	BuildType  string
+	Precedence int
+	Files      []FileProbe
+	Matched    bool
+	Reason     string
+}
+
+// FileProbe is the result of probing a marker file
+// pattern, File is the file of the repository it
+// matched, empty when the pattern is missing.
+type FileProbe struct {
+	Pattern string
+	File    string
+	Result  string
+	Error   string
+}
+
+// WithExplanation returns a context collecting the
+// explanation of the detections made with it.
+func WithExplanation(ctx context.Context) (context.Context, *Explanation) {
+	explanation := &Explanation{}
+	return context.WithValue(ctx, explanationKey{}, explanation), explanation
+}
+
+// ExplanationFrom returns the explanation collected
+// by the context, nil if there is none.
+func ExplanationFrom(ctx context.Context) *Explanation {
+	explanation, _ := ctx.Value(explanationKey{}).(*Explanation)
+	return explanation
+}
+
+// record adds a request made to the git service.
+func (e *Explanation) record(request Request) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Requests = append(e.Requests, request)
+}
+
+// probe adds the result of probing a marker file
+// pattern, in order of precedence.
+func (e *Explanation) probe(buildType BuildType, file FileProbe) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n := len(e.Probes); n == 0 || e.Probes[n-1].BuildType != buildType.BuildType {
+		e.Probes = append(e.Probes, Probe{BuildType: buildType.BuildType, Precedence: n + 1})
+	}
+	probe := &e.Probes[len(e.Probes)-1]
+	probe.Files = append(probe.Files, file)
+}
+
+// pin records the override file read
+// from the root of the repository.
+func (e *Explanation) pin(override *Override) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Override = override.File
+	e.pinned = override.BuildTool
+}
+
+// decide records why each build type matched or
+// not, and the precedence decision taken. Build
+// types listed in preferred are configured to
+// take precedence.
+func (e *Explanation) decide(detection *Detection, preferred []string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.Probes {
+		probe := &e.Probes[i]
+		for _, match := range detection.Matches {
+			if match.BuildType == probe.BuildType {
+				probe.Matched = true
+				probe.Reason = fmt.Sprintf("matched by %s with confidence %g", match.Evidence, match.Confidence)
+			}
+		}
+		if !probe.Matched {
+			probe.Reason = reasonOf(probe.Files)
+		}
+	}
+
+	var others []string
+	for i, match := range detection.Matches {
+		if i > 0 {
+			others = append(others, match.BuildType)
+		}
+	}
+	switch {
+	case len(detection.Matches) == 0:
+		e.Decision = "no build tool matched, the build tool is unknown"
+	case len(others) == 0:
+		e.Decision = fmt.Sprintf("%s is the only build tool matched", detection.BuildType)
+	case contains(preferred, detection.BuildType):
+		e.Decision = fmt.Sprintf("%s takes precedence over %s as configured by the detector precedence",
+			detection.BuildType, strings.Join(others, ", "))
+	default:
+		e.Decision = fmt.Sprintf("%s takes precedence over %s having the highest detector priority",
+			detection.BuildType, strings.Join(others, ", "))
+	}
+	if e.pinned != "" {
+		e.Decision = fmt.Sprintf("%s is pinned by %s, detection alone: %s", e.pinned, e.Override, e.Decision)
+	}
+}
+
+// reasonOf explains why none of the
+// probed files matched the build type.
+func reasonOf(files []FileProbe) string {
+	for _, file := range files {
+		if file.Result == ProbeFailed {
+			return fmt.Sprintf("predicates could not be evaluated against %s: %s", file.File, file.Error)
+		}
+	}
+	for _, file := range files {
+		if file.Result == ProbeRejected {
+			return fmt.Sprintf("%s does not satisfy the predicates of the detector", file.File)
+		}
+	}
+	return "no marker file found"
+}
+
+// traceTransport records the requests made
+// in the explanation of their context.
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+// TraceTransport returns a RoundTripper recording the
+// requests made through base, http.DefaultTransport
+// when nil, in the explanation of their context.
+func TraceTransport(base http.RoundTripper) http.RoundTripper {
+	return traceTransport{base: base}
+}
+
+// RoundTrip makes the request and records it along
+// with the status received, or the error.
+func (t traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+
+	request := Request{Method: req.Method, URL: req.URL.String()}
+	if err != nil {
+		request.Error = err.Error()
+	} else {
+		request.Status = resp.StatusCode
+	}
+	ExplanationFrom(req.Context()).record(request)
+	return resp, err
+}
@@ -0,0 +1,94 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Explanation", func() {
+	newTree := func(files map[string]string) *Tree {
+		var entries []Entry
+		for file := range files {
+			entries = append(entries, Entry{Path: file})
+		}
+		return NewTree(entries, func(ctx context.Context, path string) ([]byte, error) {
+			content, ok := files[path]
+			if !ok {
+				return nil, errors.New("unexpected fetch")
+			}
+			return []byte(content), nil
+		})
+	}
+
+	It("Not collected", func() {
+		detection, err := Detect(context.TODO(), newTree(map[string]string{"go.mod": "module app\n"}), nil)
+		Expect(err).Should(BeNil())
+		Expect(detection.BuildType).Should(Equal(Golang), "build tool type should be golang")
+		Expect(ExplanationFrom(context.TODO())).Should(BeNil(), "nothing should be collected")
+	})
+
+	It("Probes and configured precedence", func() {
+		ctx, explanation := WithExplanation(context.TODO())
+		tree := newTree(map[string]string{
+			"pom.xml":      "<project><artifactId>app</artifactId></project>",
+			"package.json": "{}",
+		})
+		_, err := Detect(ctx, tree, []string{NodeJS})
+		Expect(err).Should(BeNil())
+		Expect(explanation.Probes).Should(HaveLen(len(Detectors())), "every build type should be probed")
+
+		nodejs := explanation.Probes[0]
+		Expect(nodejs.BuildType).Should(Equal(NodeJS), "nodejs should be probed first")
+		Expect(nodejs.Precedence).Should(Equal(1), "nodejs should have the highest precedence")
+		Expect(nodejs.Matched).Should(BeTrue(), "nodejs should be matched")
+		Expect(nodejs.Reason).Should(Equal("matched by package.json with confidence 1"))
+		Expect(nodejs.Files[0]).Should(Equal(FileProbe{Pattern: "package.json", File: "package.json", Result: ProbeMatched}))
+		Expect(nodejs.Files[1]).Should(Equal(FileProbe{Pattern: "pnpm-lock.yaml", Result: ProbeMissing}))
+
+		gradle := explanation.Probes[2]
+		Expect(gradle.BuildType).Should(Equal(Gradle), "gradle should follow maven")
+		Expect(gradle.Matched).Should(BeFalse(), "gradle should not be matched")
+		Expect(gradle.Reason).Should(Equal("no marker file found"))
+		Expect(explanation.Decision).Should(Equal("nodejs takes precedence over maven as configured by the detector precedence"))
+	})
+
+	It("Pinned build tool", func() {
+		ctx, explanation := WithExplanation(context.TODO())
+		tree := newTree(map[string]string{
+			".build-tool-detector.yaml": "build-tool: gradle\n",
+			"pom.xml":                   "<project><artifactId>app</artifactId></project>",
+		})
+		files, _, err := ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil())
+		_, err = Detect(ctx, files, nil)
+		Expect(err).Should(BeNil())
+		Expect(explanation.Override).Should(Equal(".build-tool-detector.yaml"), "override file should be recorded")
+		Expect(explanation.Decision).Should(Equal("gradle is pinned by .build-tool-detector.yaml, detection alone: maven is the only build tool matched"))
+	})
+
+	It("Requests", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		ctx, explanation := WithExplanation(context.TODO())
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/repos/owner/repo", nil)
+		Expect(err).Should(BeNil())
+		client := http.Client{Transport: TraceTransport(nil)}
+		resp, err := client.Do(req.WithContext(ctx))
+		Expect(err).Should(BeNil())
+		resp.Body.Close()
+
+		Expect(explanation.Requests).Should(Equal([]Request{{
+			Method: http.MethodGet,
+			URL:    server.URL + "/repos/owner/repo",
+			Status: http.StatusNotFound,
+		}}), "request should be recorded with the status received")
+	})
+})
@@ -0,0 +1,19 @@
+package types
+
+// SnapshotRegistry saves the registered detectors and
+// returns a function restoring them, for tests to
+// leave the registry as they found it.
+func SnapshotRegistry() func() {
+	registry.RLock()
+	detectors := make(map[string]Detector, len(registry.detectors))
+	for name, detector := range registry.detectors {
+		detectors[name] = detector
+	}
+	registry.RUnlock()
+
+	return func() {
+		registry.Lock()
+		registry.detectors = detectors
+		registry.Unlock()
+	}
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// SpringBoot application built on spring boot.
+	SpringBoot = "spring-boot"
+
+	// Quarkus application built on quarkus.
+	Quarkus = "quarkus"
+
+	// VertX application built on eclipse vert.x.
+	VertX = "vertx"
+
+	// Thorntail application built on thorntail,
+	// formerly wildfly swarm.
+	Thorntail = "thorntail"
+
+	// WildFly application deployed on wildfly.
+	WildFly = "wildfly"
+)
+
+// Framework is the runtime framework a java
+// application is built on. The version is empty
+// if it could not be resolved.
+type Framework struct {
+	Name    string
+	Version string
+}
+
+// frameworkGroups maps the group ids of the parents,
+// BOMs, plugins and dependencies of a java build to
+// the framework. Group ids are matched by prefix,
+// the first matching prefix wins.
+var frameworkGroups = []struct {
+	prefix    string
+	framework string
+}{
+	{"org.springframework.boot", SpringBoot},
+	{"io.quarkus", Quarkus},
+	{"io.vertx", VertX},
+	{"io.reactiverse", VertX},
+	{"io.thorntail", Thorntail},
+	{"org.wildfly.swarm", Thorntail},
+	{"org.wildfly", WildFly},
+}
+
+// frameworkPlugins maps the ids of
+// gradle plugins to the framework.
+var frameworkPlugins = map[string]string{
+	"org.springframework.boot": SpringBoot,
+	"io.quarkus":               Quarkus,
+	"io.vertx.vertx-plugin":    VertX,
+	"io.thorntail.thorntail":   Thorntail,
+}
+
+var (
+	// gradlePlugin matches the plugins applied by a gradle
+	// build script, along with their version if any.
+	gradlePlugin = regexp.MustCompile(`(?:\bid|apply\s+plugin:)\s*\(?\s*['"]([\w.-]+)['"]\s*\)?(?:\s+version\s*\(?\s*['"]([^'"]+)['"])?`)
+
+	// gradleCoordinates matches the group:artifact:version
+	// coordinates declared by a gradle build script.
+	gradleCoordinates = regexp.MustCompile(`['"]([\w.-]+):([\w.-]+)(?::([^'"@:]+))?['"]`)
+)
+
+// frameworkOf returns the framework
+// the group id belongs to, if any.
+func frameworkOf(groupID string) string {
+	for _, group := range frameworkGroups {
+		if groupID == group.prefix || strings.HasPrefix(groupID, group.prefix+".") {
+			return group.framework
+		}
+	}
+	return ""
+}
+
+// frameworkFinder identifies the framework from the
+// artifacts of a build, in the order they are added.
+// The first artifact belonging to a framework names
+// it, the first one with a version sets its version.
+type frameworkFinder struct {
+	framework *Framework
+}
+
+// add inspects the artifact, versions which are
+// empty or not resolved are ignored.
+func (f *frameworkFinder) add(framework string, version string) {
+	if framework == "" {
+		return
+	}
+	if f.framework == nil {
+		f.framework = &Framework{Name: framework}
+	}
+	if f.framework.Name == framework && f.framework.Version == "" && !strings.Contains(version, "$") {
+		f.framework.Version = strings.TrimSpace(version)
+	}
+}
+
+// addGradle inspects the plugins, then the
+// dependencies of the gradle build script.
+func (f *frameworkFinder) addGradle(content []byte) {
+	script := string(content)
+	for _, plugin := range gradlePlugin.FindAllStringSubmatch(script, -1) {
+		f.add(frameworkPlugins[plugin[1]], plugin[2])
+	}
+	for _, coordinates := range gradleCoordinates.FindAllStringSubmatch(script, -1) {
+		f.add(frameworkOf(coordinates[1]), coordinates[3])
+	}
+}
@@ -0,0 +1,197 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"path"
+	"strings"
+)
+
+const (
+	goMod     = "go.mod"
+	gopkgTOML = "Gopkg.toml"
+	glideYAML = "glide.yaml"
+	cmdDir    = "cmd"
+
+	// GoModules dependencies managed with go modules.
+	GoModules = "modules"
+
+	// Dep dependencies managed with dep.
+	Dep = "dep"
+
+	// Glide dependencies managed with glide.
+	Glide = "glide"
+
+	moduleDirective  = "module"
+	goDirective      = "go"
+	glidePackage     = "package:"
+	packageClause    = "package"
+	mainPackage      = "main"
+	goExtension      = ".go"
+	goTestFileSuffix = "_test.go"
+)
+
+// GolangBuild holds the details
+// of a go project.
+type GolangBuild struct {
+	Module            string
+	GoVersion         string
+	DependencyManager string
+	MainPackages      []string
+}
+
+// golangDetector recognizes go projects by
+// their module or dependency manager files.
+type golangDetector struct {
+	Detector
+}
+
+// Confidence returns the confidence with which
+// the marker file identifies a go project. A
+// root main.go without module or dependency
+// manager files is weak evidence.
+func (g golangDetector) Confidence(file string) float64 {
+	switch file {
+	case goMod:
+		return fullConfidence
+	case gopkgTOML, glideYAML:
+		return 0.9
+	default:
+		return 0.5
+	}
+}
+
+// Describe reports the module path, the go directive
+// and the main packages found under cmd/. The go.mod
+// file takes precedence over Gopkg.toml and glide.yaml.
+// The main packages are found on a best effort basis.
+func (g golangDetector) Describe(ctx context.Context, src Source, match *Match) error {
+	build := GolangBuild{}
+	match.Golang = &build
+
+	switch {
+	case contains(match.Files, goMod):
+		build.DependencyManager = GoModules
+		content, err := src.ReadFile(ctx, goMod)
+		if err != nil {
+			return err
+		}
+		build.Module, build.GoVersion = parseGoMod(content)
+	case contains(match.Files, gopkgTOML):
+		build.DependencyManager = Dep
+	case contains(match.Files, glideYAML):
+		build.DependencyManager = Glide
+		content, err := src.ReadFile(ctx, glideYAML)
+		if err != nil {
+			return err
+		}
+		build.Module = parseGlidePackage(content)
+	}
+	match.Runtime = newRuntime(GoRuntime, build.GoVersion)
+	build.MainPackages = mainPackagesOf(ctx, src)
+	return nil
+}
+
+// parseGoMod returns the module path and
+// the go directive of a go.mod file.
+func parseGoMod(content []byte) (module string, goVersion string) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case moduleDirective:
+			module = strings.Trim(fields[1], "\"`")
+		case goDirective:
+			goVersion = fields[1]
+		}
+	}
+	return module, goVersion
+}
+
+// parseGlidePackage returns the
+// package of a glide.yaml file.
+func parseGlidePackage(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, glidePackage) {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, glidePackage)), "\"'")
+		}
+	}
+	return ""
+}
+
+// mainPackagesOf returns the directories directly
+// under cmd/ containing a main package. Directories
+// which can not be read are skipped, as is a
+// missing cmd/ directory.
+func mainPackagesOf(ctx context.Context, src Source) []string {
+	entries, err := src.ReadDir(ctx, cmdDir)
+	if err != nil {
+		return nil
+	}
+
+	var mainPackages []string
+	for _, entry := range entries {
+		if !entry.Dir {
+			continue
+		}
+		if isMain, err := isMainPackage(ctx, src, entry.Path); err == nil && isMain {
+			mainPackages = append(mainPackages, entry.Path)
+		}
+	}
+	return mainPackages
+}
+
+// isMainPackage returns whether the first go
+// file of the directory declares package main.
+func isMainPackage(ctx context.Context, src Source, dir string) (bool, error) {
+	entries, err := src.ReadDir(ctx, dir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.Dir || path.Ext(entry.Path) != goExtension || strings.HasSuffix(entry.Path, goTestFileSuffix) {
+			continue
+		}
+		content, err := src.ReadFile(ctx, entry.Path)
+		if err != nil {
+			return false, err
+		}
+		return packageOf(content) == mainPackage, nil
+	}
+	return false, nil
+}
+
+// packageOf returns the package name
+// declared by a go source file.
+func packageOf(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) >= 2 && fields[0] == packageClause {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// stripComment removes a trailing
+// line comment.
+func stripComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// getTypeGolang returns the Detector for golang.
+func getTypeGolang() Detector {
+	return golangDetector{
+		NewDetector(Golang, golangPriority, NewGolang, goMod, gopkgTOML, glideYAML, mainFile),
+	}
+}
@@ -0,0 +1,73 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Golang", func() {
+	ctx := context.TODO()
+
+	Context("Describe", func() {
+		It("Go module with commands", func() {
+			src := mapSource{
+				"go.mod":                 "module github.com/fabric8-services/build-tool-detector // service\n\ngo 1.12\n\nrequire (\n\tgithub.com/pkg/errors v0.8.1\n)\n",
+				"cmd/server/main.go":     "// Command server.\npackage main\n",
+				"cmd/cli/cli_test.go":    "package main_test\n",
+				"cmd/cli/cli.go":         "package main\n",
+				"cmd/internal/helper.go": "package internal\n",
+				"cmd/README.md":          "commands",
+			}
+			detection := NewDetection([]BuildType{{BuildType: Golang, File: "go.mod"}, {BuildType: Golang, File: "main.go"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+
+			golang := detection.Top().Golang
+			Expect(detection.Top().Evidence).Should(BeEquivalentTo("go.mod"), "evidence should be 'go.mod'")
+			Expect(golang.Module).Should(BeEquivalentTo("github.com/fabric8-services/build-tool-detector"), "module should be parsed from go.mod")
+			Expect(golang.GoVersion).Should(BeEquivalentTo("1.12"), "go version should be '1.12'")
+			Expect(golang.DependencyManager).Should(BeEquivalentTo("modules"), "dependency manager should be 'modules'")
+			Expect(golang.MainPackages).Should(ConsistOf("cmd/server", "cmd/cli"), "main packages should be found under cmd/")
+		})
+
+		It("Go module - commands unavailable", func() {
+			tree := NewTree([]Entry{{Path: "go.mod"}, {Path: "cmd/server/main.go"}, {Path: "cmd/cli/cli.go"}}, func(ctx context.Context, file string) ([]byte, error) {
+				switch file {
+				case "go.mod":
+					return []byte("module github.com/fabric8-services/build-tool-detector\n\ngo 1.12\n"), nil
+				case "cmd/cli/cli.go":
+					return []byte("package main\n"), nil
+				}
+				return nil, errors.New("unable to fetch")
+			})
+			detection := NewDetection([]BuildType{{BuildType: Golang, File: "go.mod"}})
+			Expect(detection.Describe(ctx, tree)).Should(BeNil())
+
+			golang := detection.Top().Golang
+			Expect(golang.Module).Should(BeEquivalentTo("github.com/fabric8-services/build-tool-detector"), "module should be parsed from go.mod")
+			Expect(golang.GoVersion).Should(BeEquivalentTo("1.12"), "go version should be '1.12'")
+			Expect(golang.MainPackages).Should(ConsistOf("cmd/cli"), "commands which can not be fetched should be skipped")
+			Expect(detection.Top().Runtime).Should(Equal(&Runtime{Name: GoRuntime, Version: "1.12"}), "runtime should be reported")
+		})
+
+		It("Glide without commands", func() {
+			src := mapSource{"glide.yaml": "package: github.com/fabric8-services/fabric8-wit\nimport:\n- package: github.com/pkg/errors\n"}
+			detection := NewDetection([]BuildType{{BuildType: Golang, File: "glide.yaml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+
+			Expect(detection.Top().Golang).Should(Equal(&GolangBuild{Module: "github.com/fabric8-services/fabric8-wit", DependencyManager: Glide}), "module should be parsed from glide.yaml")
+		})
+
+		It("Root main.go only", func() {
+			detection := NewDetection([]BuildType{{BuildType: Golang, File: "main.go"}})
+			Expect(detection.Describe(ctx, mapSource{"main.go": "package main\n"})).Should(BeNil())
+
+			Expect(detection.Top().Confidence).Should(BeNumerically("<", 1), "main.go alone should not have full confidence")
+			Expect(detection.Top().Golang).Should(Equal(&GolangBuild{}), "no module should be reported")
+		})
+	})
+})
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/app"
+)
+
+const (
+	// Gradle build type detected gradle.
+	Gradle = "gradle"
+
+	buildGradle       = "build.gradle"
+	buildGradleKts    = "build.gradle.kts"
+	settingsGradle    = "settings.gradle"
+	settingsGradleKts = "settings.gradle.kts"
+	gradlew           = "gradlew"
+
+	// GroovyDSL gradle build written in groovy.
+	GroovyDSL = "groovy"
+
+	// KotlinDSL gradle build written in kotlin.
+	KotlinDSL = "kotlin"
+
+	gradlePriority = 250
+	kotlinScript   = ".kts"
+)
+
+// gradleJavaVersion matches the source compatibility
+// or the toolchain version of a gradle build script.
+var gradleJavaVersion = regexp.MustCompile(`(?:sourceCompatibility|languageVersion)\s*(?:=|\.set\()?\s*(?:JavaVersion\.VERSION_|JavaLanguageVersion\.of\()?['"]?(\d[\d._]*)`)
+
+// GradleBuild holds the details
+// of a gradle build.
+type GradleBuild struct {
+	DSL     string
+	Wrapper bool
+}
+
+// gradleDetector recognizes gradle builds
+// and reports their DSL and wrapper.
+type gradleDetector struct {
+	Detector
+}
+
+// NewGradle will create a buildToolDetector
+// struct with the BuildToolType set
+// to gradle.
+func NewGradle() *app.GoaBuildToolDetector {
+	return &app.GoaBuildToolDetector{
+		BuildToolType: Gradle,
+	}
+}
+
+// Confidence returns the confidence with which
+// the marker file identifies a gradle build. A
+// settings file or wrapper alone is weaker
+// evidence than a build script.
+func (g gradleDetector) Confidence(file string) float64 {
+	switch file {
+	case buildGradle, buildGradleKts:
+		return fullConfidence
+	case settingsGradle, settingsGradleKts:
+		return 0.9
+	default:
+		return 0.8
+	}
+}
+
+// Describe reports the DSL used by the build
+// scripts and whether the wrapper is present.
+// The framework is identified from the plugins
+// and dependencies of the build script, the java
+// version from .java-version or else from the
+// source compatibility of the build script.
+func (g gradleDetector) Describe(ctx context.Context, src Source, match *Match) error {
+	build := GradleBuild{
+		DSL: GroovyDSL,
+	}
+	for _, file := range match.Files {
+		if strings.HasSuffix(file, kotlinScript) {
+			build.DSL = KotlinDSL
+		}
+		if file == gradlew {
+			build.Wrapper = true
+		}
+	}
+	match.Gradle = &build
+
+	finder := frameworkFinder{}
+	var scriptVersion string
+	for _, file := range match.Files {
+		if file != buildGradle && file != buildGradleKts {
+			continue
+		}
+		content, err := src.ReadFile(ctx, file)
+		if err != nil {
+			return err
+		}
+		finder.addGradle(content)
+		if found := gradleJavaVersion.FindSubmatch(content); found != nil && scriptVersion == "" {
+			scriptVersion = strings.Replace(string(found[1]), "_", ".", -1)
+		}
+	}
+	match.Framework = finder.framework
+
+	javaVersion, err := readVersionFile(ctx, src, javaVersionFile)
+	if err != nil {
+		return err
+	}
+	match.Runtime = newRuntime(JavaRuntime, javaVersion, scriptVersion)
+	return nil
+}
+
+// getTypeGradle returns the Detector for gradle.
+func getTypeGradle() Detector {
+	return gradleDetector{
+		NewDetector(Gradle, gradlePriority, NewGradle, buildGradle, buildGradleKts, settingsGradle, settingsGradleKts, gradlew),
+	}
+}
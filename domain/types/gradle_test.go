@@ -0,0 +1,50 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Gradle", func() {
+	ctx := context.TODO()
+
+	Context("Describe", func() {
+		It("Groovy DSL without wrapper", func() {
+			detection := NewDetection([]BuildType{{BuildType: Gradle, File: "settings.gradle"}, {BuildType: Gradle, File: "build.gradle"}})
+			Expect(detection.Describe(ctx, mapSource{"build.gradle": "apply plugin: 'java'"})).Should(BeNil())
+
+			Expect(detection.BuildType).Should(BeEquivalentTo("gradle"), "build tool type should be 'gradle'")
+			Expect(detection.Top().Evidence).Should(BeEquivalentTo("build.gradle"), "evidence should be 'build.gradle'")
+			Expect(detection.Top().Gradle).Should(Equal(&GradleBuild{DSL: GroovyDSL, Wrapper: false}), "dsl should be groovy without wrapper")
+			Expect(detection.Top().Framework).Should(BeNil(), "there should be no framework")
+		})
+
+		It("Kotlin DSL with wrapper", func() {
+			detection := NewDetection([]BuildType{{BuildType: Gradle, File: "build.gradle.kts"}, {BuildType: Gradle, File: "gradlew"}})
+			Expect(detection.Describe(ctx, mapSource{"build.gradle.kts": `plugins { id("io.quarkus") version "1.2.0.Final" }`})).Should(BeNil())
+
+			Expect(detection.Top().Confidence).Should(BeEquivalentTo(1), "confidence should be 1")
+			Expect(detection.Top().Gradle).Should(Equal(&GradleBuild{DSL: KotlinDSL, Wrapper: true}), "dsl should be kotlin with wrapper")
+			Expect(detection.Top().Framework).Should(Equal(&Framework{Name: Quarkus, Version: "1.2.0.Final"}), "framework should be read from the plugins")
+		})
+
+		It("Framework from dependencies", func() {
+			detection := NewDetection([]BuildType{{BuildType: Gradle, File: "build.gradle"}})
+			src := mapSource{"build.gradle": `dependencies {
+				implementation 'io.vertx:vertx-core:3.8.0'
+				implementation "io.vertx:vertx-web:$vertxVersion"
+			}`}
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Framework).Should(Equal(&Framework{Name: VertX, Version: "3.8.0"}), "framework should be read from the dependencies")
+		})
+
+		It("Wrapper only", func() {
+			detection := NewDetection([]BuildType{{BuildType: Gradle, File: "gradlew"}})
+			Expect(detection.Top().Confidence).Should(BeNumerically("<", 1), "wrapper alone should not have full confidence")
+		})
+	})
+})
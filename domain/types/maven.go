@@ -0,0 +1,303 @@
+package types
+
+import (
+	"context"
+	"encoding/xml"
+	"path"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/app"
+	"github.com/fabric8-services/build-tool-detector/log"
+)
+
+const (
+	// JarPackaging maven module packaged as a jar,
+	// the default packaging.
+	JarPackaging = "jar"
+
+	// WarPackaging maven module packaged as a war.
+	WarPackaging = "war"
+
+	// EarPackaging maven module packaged as an ear.
+	EarPackaging = "ear"
+
+	// PomPackaging maven module aggregating
+	// modules or sharing configuration.
+	PomPackaging = "pom"
+
+	xmlExtension   = ".xml"
+	importScope    = "import"
+	maxModuleDepth = 10
+)
+
+// executablePlugins are the plugins packaging
+// a jar module as a deployable application.
+var executablePlugins = map[string]bool{
+	"spring-boot-maven-plugin": true,
+	"quarkus-maven-plugin":     true,
+	"thorntail-maven-plugin":   true,
+	"vertx-maven-plugin":       true,
+	"maven-shade-plugin":       true,
+	"maven-assembly-plugin":    true,
+}
+
+// javaVersionProperties are the properties declaring
+// the java version, by order of precedence.
+var javaVersionProperties = []string{"maven.compiler.release", "maven.compiler.source", "java.version"}
+
+// MavenModule holds the coordinates and packaging of
+// a maven module. Path is the directory of the module
+// and Modules the paths of the modules it declares,
+// relative to the root of the repository.
+type MavenModule struct {
+	Path       string
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Packaging  string
+	Deployable bool
+	Modules    []string
+}
+
+// MavenBuild holds the details of a maven build,
+// the root module and the modules of the reactor,
+// depth first with each parent before its modules,
+// in declaration order.
+type MavenBuild struct {
+	MavenModule
+	Reactor []MavenModule
+}
+
+// pom is the subset of the maven
+// project model used for detection.
+type pom struct {
+	GroupID    string   `xml:"groupId"`
+	ArtifactID string   `xml:"artifactId"`
+	Version    string   `xml:"version"`
+	Packaging  string   `xml:"packaging"`
+	Parent     artifact `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Modules      []string   `xml:"modules>module"`
+	Managed      []artifact `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies []artifact `xml:"dependencies>dependency"`
+	Plugins      []artifact `xml:"build>plugins>plugin"`
+}
+
+// artifact is a parent, dependency
+// or plugin of a maven project.
+type artifact struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+// mavenDetector recognizes maven builds and
+// reports the modules of the reactor.
+type mavenDetector struct {
+	Detector
+}
+
+// Describe reports the coordinates and packaging of
+// the root pom.xml and of the modules it declares,
+// recursively. Modules whose pom.xml is missing
+// or malformed are skipped. The framework is identified from
+// the root pom.xml first, then from the modules.
+// The java version is read from .java-version, or
+// else from the compiler properties of the root.
+func (m mavenDetector) Describe(ctx context.Context, src Source, match *Match) error {
+	reader := pomReader{src: src, seen: make(map[string]bool)}
+	root, err := reader.read(ctx, pomXML, 0)
+	if err != nil {
+		return err
+	}
+	match.Maven = &MavenBuild{
+		MavenModule: *root,
+		Reactor:     reader.reactor,
+	}
+
+	properties := propertiesOf(reader.poms[0], nil)
+	finder := frameworkFinder{}
+	for _, project := range reader.poms {
+		finder.addPOM(project, propertiesOf(project, properties))
+	}
+	match.Framework = finder.framework
+
+	javaVersion, err := readVersionFile(ctx, src, javaVersionFile)
+	if err != nil {
+		return err
+	}
+	versions := []string{javaVersion}
+	for _, property := range javaVersionProperties {
+		versions = append(versions, resolve(properties[property], properties))
+	}
+	match.Runtime = newRuntime(JavaRuntime, versions...)
+	return nil
+}
+
+// pomReader reads the pom.xml files of
+// the modules of a reactor.
+type pomReader struct {
+	src     Source
+	seen    map[string]bool
+	poms    []pom
+	reactor []MavenModule
+}
+
+// read parses the pom.xml file and the poms of the
+// modules it declares. Modules are added to the
+// reactor before their own modules. Malformed
+// module poms are logged and skipped, cycles and
+// nesting deeper than maxModuleDepth are not
+// followed.
+func (r *pomReader) read(ctx context.Context, file string, depth int) (*MavenModule, error) {
+	r.seen[file] = true
+	content, err := r.src.ReadFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	var project pom
+	if err := xml.Unmarshal(content, &project); err != nil {
+		if depth == 0 {
+			return nil, err
+		}
+		log.Logger().WithError(err).WithField(fileField, file).Warnf("malformed module pom skipped")
+		return nil, nil
+	}
+	r.poms = append(r.poms, project)
+
+	dir := path.Dir(file)
+	module := newMavenModule(dir, project)
+	index := len(r.reactor)
+	if depth > 0 {
+		r.reactor = append(r.reactor, module)
+	}
+	if depth >= maxModuleDepth {
+		return &module, nil
+	}
+	for _, name := range project.Modules {
+		modulePOM := modulePOMOf(dir, name)
+		module.Modules = append(module.Modules, path.Dir(modulePOM))
+		if r.seen[modulePOM] {
+			continue
+		}
+		_, err := r.read(ctx, modulePOM, depth+1)
+		if err == ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if depth > 0 {
+		r.reactor[index] = module
+	}
+	return &module, nil
+}
+
+// newMavenModule creates the module from its pom,
+// the group id and version are inherited from
+// the parent if not declared.
+func newMavenModule(dir string, project pom) MavenModule {
+	module := MavenModule{
+		Path:       dir,
+		GroupID:    strings.TrimSpace(project.GroupID),
+		ArtifactID: strings.TrimSpace(project.ArtifactID),
+		Version:    strings.TrimSpace(project.Version),
+		Packaging:  strings.TrimSpace(project.Packaging),
+	}
+	if module.GroupID == "" {
+		module.GroupID = strings.TrimSpace(project.Parent.GroupID)
+	}
+	if module.Version == "" {
+		module.Version = strings.TrimSpace(project.Parent.Version)
+	}
+	if module.Packaging == "" {
+		module.Packaging = JarPackaging
+	}
+
+	switch module.Packaging {
+	case WarPackaging, EarPackaging:
+		module.Deployable = true
+	case JarPackaging:
+		for _, plugin := range project.Plugins {
+			if executablePlugins[strings.TrimSpace(plugin.ArtifactID)] {
+				module.Deployable = true
+			}
+		}
+	}
+	return module
+}
+
+// propertiesOf returns the properties of the
+// project, overriding the inherited ones.
+func propertiesOf(project pom, inherited map[string]string) map[string]string {
+	properties := make(map[string]string, len(inherited)+len(project.Properties.Entries))
+	for name, value := range inherited {
+		properties[name] = value
+	}
+	for _, entry := range project.Properties.Entries {
+		properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+	properties["project.version"] = strings.TrimSpace(project.Version)
+	properties["project.parent.version"] = strings.TrimSpace(project.Parent.Version)
+	return properties
+}
+
+// resolve replaces a version made of a
+// single property reference by its value.
+func resolve(version string, properties map[string]string) string {
+	version = strings.TrimSpace(version)
+	if strings.HasPrefix(version, "${") && strings.HasSuffix(version, "}") {
+		return properties[strings.TrimSuffix(strings.TrimPrefix(version, "${"), "}")]
+	}
+	return version
+}
+
+// addPOM inspects the parent, the imported BOMs, the
+// plugins and the dependencies of the maven project.
+func (f *frameworkFinder) addPOM(project pom, properties map[string]string) {
+	artifacts := []artifact{project.Parent}
+	for _, managed := range project.Managed {
+		if strings.TrimSpace(managed.Scope) == importScope {
+			artifacts = append(artifacts, managed)
+		}
+	}
+	artifacts = append(artifacts, project.Plugins...)
+	artifacts = append(artifacts, project.Dependencies...)
+
+	for _, a := range artifacts {
+		f.add(frameworkOf(strings.TrimSpace(a.GroupID)), resolve(a.Version, properties))
+	}
+}
+
+// modulePOMOf returns the path of the pom.xml of the
+// module, modules may name the pom file directly.
+func modulePOMOf(dir string, name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasSuffix(name, xmlExtension) {
+		return path.Join(dir, name)
+	}
+	return path.Join(dir, name, pomXML)
+}
+
+// NewMaven will create a buildToolDetector
+// struct with the BuildToolType set
+// to maven.
+func NewMaven() *app.GoaBuildToolDetector {
+	return &app.GoaBuildToolDetector{
+		BuildToolType: Maven,
+	}
+}
+
+// getTypeMaven returns the Detector for maven.
+func getTypeMaven() Detector {
+	return mavenDetector{
+		NewDetector(Maven, mavenPriority, NewMaven, pomXML),
+	}
+}
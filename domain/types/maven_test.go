@@ -0,0 +1,142 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Maven", func() {
+	ctx := context.TODO()
+
+	Context("Describe", func() {
+		It("Multi-module reactor", func() {
+			src := mapSource{
+				"pom.xml": `<project>
+					<groupId>io.fabric8.launcher</groupId>
+					<artifactId>launcher-parent</artifactId>
+					<version>1.0.0-SNAPSHOT</version>
+					<packaging>pom</packaging>
+					<modules>
+						<module>core</module>
+						<module>web</module>
+						<module>missing</module>
+					</modules>
+				</project>`,
+				"core/pom.xml": `<project>
+					<parent><groupId>io.fabric8.launcher</groupId><version>1.0.0-SNAPSHOT</version></parent>
+					<artifactId>launcher-core</artifactId>
+					<modules><module>../web/pom.xml</module></modules>
+				</project>`,
+				"web/pom.xml": `<project>
+					<parent><groupId>io.fabric8.launcher</groupId><version>1.0.0-SNAPSHOT</version></parent>
+					<artifactId>launcher-web</artifactId>
+					<build><plugins><plugin><artifactId>spring-boot-maven-plugin</artifactId></plugin></plugins></build>
+				</project>`,
+			}
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+
+			maven := detection.Top().Maven
+			Expect(maven.MavenModule).Should(Equal(MavenModule{
+				Path:       ".",
+				GroupID:    "io.fabric8.launcher",
+				ArtifactID: "launcher-parent",
+				Version:    "1.0.0-SNAPSHOT",
+				Packaging:  PomPackaging,
+				Modules:    []string{"core", "web", "missing"},
+			}), "root module should be an aggregator")
+			Expect(maven.Reactor).Should(HaveLen(2), "missing modules should be skipped and modules read once")
+			Expect(maven.Reactor[1].ArtifactID).Should(Equal("launcher-web"), "modules should be listed after their parent")
+			Expect(maven.Reactor[1].Deployable).Should(BeTrue(), "spring boot jar should be deployable")
+			Expect(maven.Reactor[0]).Should(Equal(MavenModule{
+				Path:       "core",
+				GroupID:    "io.fabric8.launcher",
+				ArtifactID: "launcher-core",
+				Version:    "1.0.0-SNAPSHOT",
+				Packaging:  JarPackaging,
+				Modules:    []string{"web"},
+			}), "coordinates should be inherited from the parent")
+		})
+
+		It("Reactor order", func() {
+			src := mapSource{
+				"pom.xml":           "<project><artifactId>parent</artifactId><modules><module>api</module><module>web</module></modules></project>",
+				"api/pom.xml":       "<project><artifactId>api</artifactId><modules><module>model</module></modules></project>",
+				"api/model/pom.xml": "<project><artifactId>model</artifactId></project>",
+				"web/pom.xml":       "<project><artifactId>web</artifactId></project>",
+			}
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+
+			var artifacts []string
+			for _, module := range detection.Top().Maven.Reactor {
+				artifacts = append(artifacts, module.ArtifactID)
+			}
+			Expect(artifacts).Should(Equal([]string{"api", "model", "web"}), "parents should be listed before their modules, in declaration order")
+		})
+
+		It("Malformed module", func() {
+			src := mapSource{
+				"pom.xml":     "<project><artifactId>parent</artifactId><modules><module>api</module><module>web</module></modules></project>",
+				"api/pom.xml": "<project><artifactId>api</artifactId>",
+				"web/pom.xml": "<project><artifactId>web</artifactId></project>",
+			}
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+
+			maven := detection.Top().Maven
+			Expect(maven.ArtifactID).Should(Equal("parent"), "root module should be described")
+			Expect(maven.Reactor).Should(HaveLen(1), "malformed modules should be skipped")
+			Expect(maven.Reactor[0].ArtifactID).Should(Equal("web"), "remaining modules should be described")
+		})
+
+		It("War packaging", func() {
+			src := mapSource{"pom.xml": "<project><groupId>org.example</groupId><artifactId>app</artifactId><version>1</version><packaging>war</packaging></project>"}
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Maven.Deployable).Should(BeTrue(), "war should be deployable")
+			Expect(detection.Top().Maven.Reactor).Should(BeEmpty(), "there should be no modules")
+			Expect(detection.Top().Framework).Should(BeNil(), "there should be no framework")
+		})
+
+		It("Spring Boot parent", func() {
+			src := mapSource{"pom.xml": `<project>
+				<parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId><version>2.1.4.RELEASE</version></parent>
+				<artifactId>app</artifactId>
+			</project>`}
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Framework).Should(Equal(&Framework{Name: SpringBoot, Version: "2.1.4.RELEASE"}), "framework should be read from the parent")
+		})
+
+		It("Quarkus BOM in a module", func() {
+			src := mapSource{
+				"pom.xml": `<project>
+					<groupId>org.example</groupId><artifactId>parent</artifactId><version>1</version><packaging>pom</packaging>
+					<properties><quarkus.version>1.3.2.Final</quarkus.version></properties>
+					<modules><module>app</module></modules>
+				</project>`,
+				"app/pom.xml": `<project>
+					<artifactId>app</artifactId>
+					<dependencyManagement><dependencies><dependency>
+						<groupId>io.quarkus</groupId><artifactId>quarkus-bom</artifactId><version>${quarkus.version}</version><scope>import</scope>
+					</dependency></dependencies></dependencyManagement>
+					<dependencies><dependency><groupId>io.quarkus</groupId><artifactId>quarkus-resteasy</artifactId></dependency></dependencies>
+				</project>`,
+			}
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Framework).Should(Equal(&Framework{Name: Quarkus, Version: "1.3.2.Final"}), "version should be resolved from the root properties")
+		})
+
+		It("Malformed pom.xml", func() {
+			detection := NewDetection([]BuildType{{BuildType: Maven, File: "pom.xml"}})
+			Expect(detection.Describe(ctx, mapSource{"pom.xml": "<project>"})).ShouldNot(BeNil())
+			Expect(detection.Top().Maven).Should(BeNil(), "no details should be reported")
+		})
+	})
+})
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	packageLockJSON   = "package-lock.json"
+	npmShrinkwrapJSON = "npm-shrinkwrap.json"
+	yarnLock          = "yarn.lock"
+	pnpmLockYAML      = "pnpm-lock.yaml"
+	tsconfigJSON      = "tsconfig.json"
+	typescriptPackage = "typescript"
+	versionSeparator  = "@"
+
+	// NPM node project installed with npm.
+	NPM = "npm"
+
+	// Yarn node project installed with yarn.
+	Yarn = "yarn"
+
+	// PNPM node project installed with pnpm.
+	PNPM = "pnpm"
+
+	// Express application built on express.
+	Express = "express"
+
+	// React application built on react.
+	React = "react"
+
+	// Angular application built on angular.
+	Angular = "angular"
+
+	// Vue application built on vue.js.
+	Vue = "vue"
+
+	// NextJS application built on next.js.
+	NextJS = "nextjs"
+)
+
+// npmScripts are the scripts reported
+// when declared by package.json.
+var npmScripts = []string{"build", "start", "test"}
+
+// packageManagers are the package managers
+// reported, others are ignored.
+var packageManagers = map[string]bool{NPM: true, Yarn: true, PNPM: true}
+
+// nodeFrameworks maps the dependencies
+// of a node project to the framework.
+var nodeFrameworks = []struct {
+	dependency string
+	framework  string
+}{
+	{"next", NextJS},
+	{"express", Express},
+	{"react", React},
+	{"@angular/core", Angular},
+	{"vue", Vue},
+}
+
+// NodeJSBuild holds the details of a node project.
+// NodeVersion is the engines.node constraint, and
+// Frameworks are ordered as in nodeFrameworks.
+// Workspaces is only set for monorepos.
+type NodeJSBuild struct {
+	PackageManager string
+	NodeVersion    string
+	Scripts        []string
+	TypeScript     bool
+	Frameworks     []string
+	Workspaces     *NodeWorkspaces
+}
+
+// packageManifest is the subset of
+// package.json used for detection.
+type packageManifest struct {
+	PackageManager string `json:"packageManager"`
+	Engines        struct {
+		Node string `json:"node"`
+	} `json:"engines"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Workspaces      json.RawMessage   `json:"workspaces"`
+}
+
+// nodeJSDetector recognizes node projects and
+// reports their package manager and frameworks.
+type nodeJSDetector struct {
+	Detector
+}
+
+// Confidence returns the confidence with which the
+// marker file identifies a node project. Lockfiles,
+// tsconfig.json and workspace configurations without
+// package.json are weaker evidence.
+func (n nodeJSDetector) Confidence(file string) float64 {
+	switch file {
+	case packageJSON:
+		return fullConfidence
+	case tsconfigJSON:
+		return 0.6
+	default:
+		return 0.8
+	}
+}
+
+// Describe reports the package manager, from the
+// packageManager field of package.json if it names
+// npm, yarn or pnpm, or else from the lockfiles,
+// along with the node constraint, the scripts,
+// typescript usage, the frameworks and the
+// workspaces of monorepos. The node version is read
+// from .nvmrc or .node-version, or else from the
+// engines.node constraint.
+func (n nodeJSDetector) Describe(ctx context.Context, src Source, match *Match) error {
+	build := NodeJSBuild{
+		PackageManager: packageManagerOf(match.Files),
+		TypeScript:     contains(match.Files, tsconfigJSON),
+	}
+	match.NodeJS = &build
+
+	var manifest packageManifest
+	if err := readJSON(ctx, src, match.Files, packageJSON, &manifest); err != nil {
+		return err
+	}
+	if manager := strings.SplitN(strings.TrimSpace(manifest.PackageManager), versionSeparator, 2)[0]; packageManagers[manager] {
+		build.PackageManager = manager
+	}
+	build.NodeVersion = strings.TrimSpace(manifest.Engines.Node)
+	for _, script := range npmScripts {
+		if _, ok := manifest.Scripts[script]; ok {
+			build.Scripts = append(build.Scripts, script)
+		}
+	}
+	if manifest.dependsOn(typescriptPackage) {
+		build.TypeScript = true
+	}
+	for _, framework := range nodeFrameworks {
+		if manifest.dependsOn(framework.dependency) {
+			build.Frameworks = append(build.Frameworks, framework.framework)
+		}
+	}
+
+	nodeVersion, err := readVersionFile(ctx, src, nvmrc, nodeVersionFile)
+	if err != nil {
+		return err
+	}
+	match.Runtime = newRuntime(NodeRuntime, nodeVersion, build.NodeVersion)
+	return describeWorkspaces(ctx, src, match.Files, manifest, &build)
+}
+
+// dependsOn returns whether the package is a
+// dependency or a development dependency.
+func (m packageManifest) dependsOn(name string) bool {
+	_, ok := m.Dependencies[name]
+	if !ok {
+		_, ok = m.DevDependencies[name]
+	}
+	return ok
+}
+
+// packageManagerOf returns the package manager
+// the lockfiles belong to, npm by default.
+func packageManagerOf(files []string) string {
+	switch {
+	case contains(files, pnpmLockYAML):
+		return PNPM
+	case contains(files, yarnLock):
+		return Yarn
+	default:
+		return NPM
+	}
+}
+
+// getTypeNodeJS returns the Detector for nodejs.
+func getTypeNodeJS() Detector {
+	return nodeJSDetector{
+		NewDetector(NodeJS, nodeJSPriority, NewNodeJS, packageJSON, pnpmLockYAML, yarnLock, packageLockJSON, npmShrinkwrapJSON, tsconfigJSON, lernaJSON, nxJSON, turboJSON, pnpmWorkspaceYAML),
+	}
+}
@@ -0,0 +1,112 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("NodeJS", func() {
+	ctx := context.TODO()
+
+	Context("Describe", func() {
+		It("Yarn lockfile", func() {
+			src := mapSource{"package.json": `{
+				"engines": {"node": ">=10"},
+				"scripts": {"start": "node server.js", "lint": "eslint .", "test": "jest"},
+				"dependencies": {"express": "^4.17.1", "react": "^16.8.0", "next": "^9.0.0"},
+				"devDependencies": {"typescript": "^3.5.0"}
+			}`}
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}, {BuildType: NodeJS, File: "yarn.lock"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Evidence).Should(Equal("package.json"), "evidence should be 'package.json'")
+			Expect(detection.Top().NodeJS).Should(Equal(&NodeJSBuild{
+				PackageManager: Yarn,
+				NodeVersion:    ">=10",
+				Scripts:        []string{"start", "test"},
+				TypeScript:     true,
+				Frameworks:     []string{NextJS, Express, React},
+			}), "package manager should be read from the lockfile")
+		})
+
+		It("Package manager field", func() {
+			src := mapSource{"package.json": `{"packageManager": "pnpm@7.1.0", "dependencies": {"@angular/core": "^8.0.0"}}`}
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}, {BuildType: NodeJS, File: "package-lock.json"}, {BuildType: NodeJS, File: "tsconfig.json"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().NodeJS).Should(Equal(&NodeJSBuild{PackageManager: PNPM, TypeScript: true, Frameworks: []string{Angular}}), "packageManager should take precedence over lockfiles")
+		})
+
+		It("Package manager field - unsupported", func() {
+			for _, manager := range []string{"bun@1.0.0", "yran@1.22.0"} {
+				src := mapSource{"package.json": `{"packageManager": "` + manager + `"}`}
+				detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}, {BuildType: NodeJS, File: "yarn.lock"}})
+				Expect(detection.Describe(ctx, src)).Should(BeNil())
+				Expect(detection.Top().NodeJS.PackageManager).Should(Equal(Yarn), "'%s' should fall back to the lockfiles", manager)
+			}
+		})
+
+		It("Lockfile only", func() {
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package-lock.json"}})
+			Expect(detection.Describe(ctx, mapSource{})).Should(BeNil())
+			Expect(detection.Top().Confidence).Should(BeNumerically("<", 1), "lockfile alone should not have full confidence")
+			Expect(detection.Top().NodeJS).Should(Equal(&NodeJSBuild{PackageManager: NPM}), "package manager should be 'npm' without workspaces")
+		})
+
+		It("Yarn workspaces", func() {
+			src := mapSource{
+				"package.json":                             `{"workspaces": {"packages": ["packages/*", "tools/**", "!packages/legacy"]}}`,
+				"packages/api/package.json":                "{}",
+				"packages/web/package.json":                "{}",
+				"packages/legacy/package.json":             "{}",
+				"packages/docs/README.md":                  "",
+				"tools/lint/rules/package.json":            "{}",
+				"tools/node_modules/left-pad/package.json": "{}",
+			}
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}, {BuildType: NodeJS, File: "yarn.lock"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().NodeJS.Workspaces).Should(Equal(&NodeWorkspaces{
+				Tool:     Yarn,
+				Packages: []string{"packages/api", "packages/web", "tools/lint/rules"},
+			}), "globs should be expanded to the directories with a package.json")
+		})
+
+		It("Turborepo with pnpm workspaces", func() {
+			src := mapSource{
+				"package.json":           `{"name": "monorepo"}`,
+				"pnpm-workspace.yaml":    "packages:\n  - 'apps/*'\n",
+				"turbo.json":             `{"pipeline": {}}`,
+				"apps/site/package.json": "{}",
+			}
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}, {BuildType: NodeJS, File: "pnpm-workspace.yaml"}, {BuildType: NodeJS, File: "turbo.json"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().NodeJS.Workspaces).Should(Equal(&NodeWorkspaces{Tool: Turborepo, Packages: []string{"apps/site"}}), "turborepo should orchestrate the workspaces")
+		})
+
+		It("Default layouts", func() {
+			src := mapSource{
+				"lerna.json":                 `{"version": "independent"}`,
+				"packages/core/package.json": "{}",
+			}
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "lerna.json"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().NodeJS.Workspaces).Should(Equal(&NodeWorkspaces{Tool: Lerna, Packages: []string{"packages/core"}}), "lerna should default to packages/*")
+
+			src = mapSource{
+				"nx.json":                 `{"workspaceLayout": {"libsDir": "modules"}}`,
+				"apps/shop/package.json":  "{}",
+				"modules/ui/package.json": "{}",
+			}
+			detection = NewDetection([]BuildType{{BuildType: NodeJS, File: "nx.json"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().NodeJS.Workspaces).Should(Equal(&NodeWorkspaces{Tool: Nx, Packages: []string{"apps/shop", "modules/ui"}}), "nx should use its workspace layout")
+		})
+
+		It("Malformed package.json", func() {
+			detection := NewDetection([]BuildType{{BuildType: NodeJS, File: "package.json"}})
+			Expect(detection.Describe(ctx, mapSource{"package.json": "{"})).ShouldNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,235 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/fabric8-services/build-tool-detector/log"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	buildToolDetectorYAML = ".build-tool-detector.yaml"
+	applicationYAML       = ".openshiftio/application.yaml"
+	buildConfigKind       = "BuildConfig"
+	fileField             = "file"
+)
+
+// overrideFiles are the files a repository may
+// commit to override the detection results, by
+// order of precedence.
+var overrideFiles = []string{buildToolDetectorYAML, applicationYAML}
+
+// Override pins the detection results of a repository,
+// committed to its root as .build-tool-detector.yaml:
+//
+//	build-tool: maven
+//	path: backend
+//	runtime:
+//	  version: "11"
+//	builder-image: registry.access.redhat.com/openjdk/openjdk-11-rhel7
+//
+// Every field is optional. File is the file it was read from.
+// The openshift template of .openshiftio/application.yaml
+// may pin the path and builder image instead.
+type Override struct {
+	BuildTool string `yaml:"build-tool"`
+	Path      string `yaml:"path"`
+	Runtime   struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"runtime"`
+	BuilderImage string `yaml:"builder-image"`
+	File         string `yaml:"-"`
+}
+
+// applicationTemplate is the subset of the openshift
+// template of .openshiftio/application.yaml which
+// holds the source and builder image of the builds.
+type applicationTemplate struct {
+	Parameters []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"parameters"`
+	Objects []struct {
+		Kind string `yaml:"kind"`
+		Spec struct {
+			Source struct {
+				ContextDir string `yaml:"contextDir"`
+			} `yaml:"source"`
+			Strategy struct {
+				SourceStrategy struct {
+					From struct {
+						Name string `yaml:"name"`
+					} `yaml:"from"`
+				} `yaml:"sourceStrategy"`
+			} `yaml:"strategy"`
+		} `yaml:"spec"`
+	} `yaml:"objects"`
+}
+
+// InvalidOverrideError is returned when the override
+// file can not be parsed or pins a build tool which
+// is not registered.
+type InvalidOverrideError struct {
+	File   string
+	Reason string
+}
+
+// Error returns the file and the reason
+// it is not a valid override.
+func (e *InvalidOverrideError) Error() string {
+	return e.File + ": " + e.Reason
+}
+
+// ReadOverride returns the override committed to the
+// root of the repository, nil if there is none or it
+// pins nothing, along with the tree to detect the
+// build tools in. The override path only applies to
+// the root of the repository. An InvalidOverrideError
+// is returned unless .build-tool-detector.yaml parses
+// and its build tool is registered, an invalid
+// .openshiftio/application.yaml is ignored.
+// The override is recorded in the explanation of
+// the context.
+func ReadOverride(ctx context.Context, tree *Tree) (*Tree, *Override, error) {
+	for _, file := range overrideFiles {
+		content, err := tree.Root().ReadFile(ctx, file)
+		if err == ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		override, err := parseOverride(file, content)
+		if err != nil && file == applicationYAML {
+			log.Logger().WithError(err).WithField(fileField, file).Warnf("invalid application template ignored")
+			continue
+		}
+		if err != nil {
+			return nil, nil, &InvalidOverrideError{File: file, Reason: err.Error()}
+		}
+		if !override.pins() {
+			continue
+		}
+		if tree.Dir() == "" && override.Path != "" {
+			if tree, err = tree.Sub(override.Path); err != nil {
+				return nil, nil, err
+			}
+		}
+		ExplanationFrom(ctx).pin(override)
+		return tree, override, nil
+	}
+	return tree, nil, nil
+}
+
+// parseOverride reads the override from the content
+// of the override file, along the schema of the file.
+func parseOverride(file string, content []byte) (*Override, error) {
+	override := Override{File: file}
+	if file == applicationYAML {
+		var template applicationTemplate
+		if err := yaml.Unmarshal(content, &template); err != nil {
+			return nil, err
+		}
+		override.Path, override.BuilderImage = template.build()
+		return &override, nil
+	}
+
+	if err := yaml.Unmarshal(content, &override); err != nil {
+		return nil, err
+	}
+	if _, ok := Lookup(override.BuildTool); override.BuildTool != "" && !ok {
+		return nil, fmt.Errorf("build tool %q is not supported", override.BuildTool)
+	}
+	return &override, nil
+}
+
+// build returns the context directory and the builder
+// image of the first build config of the template,
+// with the template parameters substituted. Values
+// referring to parameters without value are ignored.
+func (t applicationTemplate) build() (string, string) {
+	parameters := make(map[string]string)
+	for _, parameter := range t.Parameters {
+		parameters[parameter.Name] = parameter.Value
+	}
+	substitute := func(value string) string {
+		resolved := true
+		value = os.Expand(value, func(name string) string {
+			parameter, ok := parameters[name]
+			resolved = resolved && ok && parameter != ""
+			return parameter
+		})
+		if !resolved {
+			return ""
+		}
+		return value
+	}
+
+	for _, object := range t.Objects {
+		if object.Kind != buildConfigKind {
+			continue
+		}
+		dir := path.Clean(substitute(object.Spec.Source.ContextDir))
+		if dir == root {
+			dir = ""
+		}
+		return dir, substitute(object.Spec.Strategy.SourceStrategy.From.Name)
+	}
+	return "", ""
+}
+
+// pins reports whether the override pins the
+// build tool, runtime, builder image or path.
+func (o *Override) pins() bool {
+	return o.BuildTool != "" || o.Path != "" || o.BuilderImage != "" ||
+		o.Runtime.Name != "" || o.Runtime.Version != ""
+}
+
+// Apply pins the build tool, runtime and builder image
+// of the detection. A pinned build tool which was not
+// detected is matched by the override file. A runtime
+// version without name only applies to a detected
+// runtime.
+func (o *Override) Apply(detection *Detection) {
+	if o == nil || !o.pins() {
+		return
+	}
+	detection.Overridden = true
+	detection.BuilderImage = o.BuilderImage
+
+	if o.BuildTool != "" {
+		pinned := Match{
+			BuildType:  o.BuildTool,
+			Confidence: fullConfidence,
+			Evidence:   o.File,
+			Files:      []string{o.File},
+		}
+		matches := []Match{pinned}
+		for _, match := range detection.Matches {
+			if match.BuildType == o.BuildTool {
+				matches[0] = match
+				continue
+			}
+			matches = append(matches, match)
+		}
+		detection.Matches = matches
+		detection.BuildType = o.BuildTool
+	}
+
+	top := detection.Top()
+	if top == nil || (o.Runtime.Name == "" && o.Runtime.Version == "") {
+		return
+	}
+	runtime := Runtime{Name: o.Runtime.Name, Version: o.Runtime.Version}
+	if top.Runtime != nil && runtime.Name == "" {
+		runtime.Name = top.Runtime.Name
+	}
+	if runtime.Name != "" {
+		top.Runtime = &runtime
+	}
+}
@@ -0,0 +1,161 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Override", func() {
+	ctx := context.TODO()
+
+	newTree := func(files map[string]string) *Tree {
+		var entries []Entry
+		for file := range files {
+			entries = append(entries, Entry{Path: file})
+		}
+		return NewTree(entries, func(ctx context.Context, path string) ([]byte, error) {
+			content, ok := files[path]
+			if !ok {
+				return nil, errors.New("unexpected fetch")
+			}
+			return []byte(content), nil
+		})
+	}
+
+	It("No override", func() {
+		tree := newTree(map[string]string{"pom.xml": "<project/>"})
+		files, override, err := ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil())
+		Expect(override).Should(BeNil(), "there should be no override")
+		Expect(files).Should(Equal(tree), "the tree should be detected as is")
+
+		detection := NewDetection(nil)
+		override.Apply(detection)
+		Expect(detection.Overridden).Should(BeFalse(), "detection should not be overridden")
+	})
+
+	It("Pinned build tool and path", func() {
+		tree := newTree(map[string]string{
+			".build-tool-detector.yaml": "build-tool: gradle\npath: backend\nruntime:\n  name: java\n  version: \"11\"\nbuilder-image: openjdk-11\n",
+			"backend/pom.xml":           "<project><artifactId>app</artifactId></project>",
+		})
+		files, override, err := ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil())
+		Expect(files.Dir()).Should(Equal("backend"), "the override path should be detected")
+
+		detection, err := Detect(ctx, files, nil)
+		Expect(err).Should(BeNil())
+		override.Apply(detection)
+		Expect(detection.Overridden).Should(BeTrue(), "detection should be overridden")
+		Expect(detection.BuildType).Should(Equal(Gradle), "build tool should be pinned")
+		Expect(detection.BuilderImage).Should(Equal("openjdk-11"), "builder image should be pinned")
+		Expect(detection.Matches).Should(HaveLen(2), "the detected build tool should be kept")
+		Expect(detection.Top()).Should(Equal(&Match{
+			BuildType:  Gradle,
+			Confidence: 1,
+			Evidence:   ".build-tool-detector.yaml",
+			Files:      []string{".build-tool-detector.yaml"},
+			Runtime:    &Runtime{Name: JavaRuntime, Version: "11"},
+		}), "pinned build tool should be matched by the override file")
+	})
+
+	It("Requested path and runtime version", func() {
+		tree := newTree(map[string]string{
+			".build-tool-detector.yaml": "path: backend\nruntime:\n  version: \"14\"\n",
+			"web/package.json":          "{}",
+		})
+		sub, err := tree.Sub("web")
+		Expect(err).Should(BeNil())
+		files, override, err := ReadOverride(ctx, sub)
+		Expect(err).Should(BeNil())
+		Expect(files.Dir()).Should(Equal("web"), "the requested path should take precedence")
+
+		detection, err := Detect(ctx, files, nil)
+		Expect(err).Should(BeNil())
+		override.Apply(detection)
+		Expect(detection.BuildType).Should(Equal(NodeJS), "build tool should not be pinned")
+		Expect(detection.Top().Runtime).Should(Equal(&Runtime{Name: NodeRuntime, Version: "14"}), "runtime version should be pinned")
+	})
+
+	It("Nothing pinned", func() {
+		tree := newTree(map[string]string{".build-tool-detector.yaml": "# nothing pinned yet\n", "pom.xml": "<project/>"})
+		_, override, err := ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil())
+		Expect(override).Should(BeNil(), "an empty override should be ignored")
+
+		detection := NewDetection(nil)
+		(&Override{File: ".build-tool-detector.yaml"}).Apply(detection)
+		Expect(detection.Overridden).Should(BeFalse(), "detection should not be overridden")
+	})
+
+	It("Application template", func() {
+		tree := newTree(map[string]string{
+			".openshiftio/application.yaml": `apiVersion: v1
+kind: Template
+parameters:
+- name: SOURCE_REPOSITORY_DIR
+  value: backend
+- name: BUILDER_IMAGE
+  value: registry.access.redhat.com/redhat-openjdk-18/openjdk18-openshift
+objects:
+- kind: ImageStream
+  spec:
+    tags:
+    - name: latest
+- kind: BuildConfig
+  spec:
+    source:
+      contextDir: ${SOURCE_REPOSITORY_DIR}
+    strategy:
+      sourceStrategy:
+        from:
+          kind: DockerImage
+          name: ${BUILDER_IMAGE}
+`,
+			"backend/pom.xml": "<project><artifactId>app</artifactId></project>",
+		})
+		files, override, err := ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil())
+		Expect(files.Dir()).Should(Equal("backend"), "the context directory should be detected")
+
+		detection, err := Detect(ctx, files, nil)
+		Expect(err).Should(BeNil())
+		override.Apply(detection)
+		Expect(detection.Overridden).Should(BeTrue(), "detection should be overridden")
+		Expect(detection.BuildType).Should(Equal(Maven), "build tool should not be pinned")
+		Expect(detection.BuilderImage).Should(Equal("registry.access.redhat.com/redhat-openjdk-18/openjdk18-openshift"), "builder image should be pinned")
+	})
+
+	It("Application template - nothing pinned", func() {
+		tree := newTree(map[string]string{
+			".openshiftio/application.yaml": "apiVersion: v1\nkind: Template\nparameters:\n- name: SOURCE_REPOSITORY_URL\nobjects:\n- kind: Service\n",
+			"pom.xml":                       "<project/>",
+		})
+		files, override, err := ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil())
+		Expect(override).Should(BeNil(), "templates without build config should be ignored")
+		Expect(files).Should(Equal(tree), "the tree should be detected as is")
+
+		tree = newTree(map[string]string{".openshiftio/application.yaml": "objects: [\n", "pom.xml": "<project/>"})
+		_, override, err = ReadOverride(ctx, tree)
+		Expect(err).Should(BeNil(), "invalid templates should not be rejected")
+		Expect(override).Should(BeNil(), "invalid templates should be ignored")
+	})
+
+	It("Invalid override", func() {
+		_, _, err := ReadOverride(ctx, newTree(map[string]string{".build-tool-detector.yaml": "build-tool: ant\n"}))
+		Expect(err).Should(MatchError(`.build-tool-detector.yaml: build tool "ant" is not supported`))
+		Expect(err).Should(BeAssignableToTypeOf(&InvalidOverrideError{}), "unsupported build tool should be invalid")
+
+		_, _, err = ReadOverride(ctx, newTree(map[string]string{".build-tool-detector.yaml": "build-tool: [maven\n"}))
+		Expect(err).Should(BeAssignableToTypeOf(&InvalidOverrideError{}), "malformed yaml should be invalid")
+
+		_, _, err = ReadOverride(ctx, newTree(map[string]string{".build-tool-detector.yaml": "path: missing\n"}))
+		Expect(err).Should(Equal(ErrResourceNotFound), "missing override path should not be found")
+	})
+})
@@ -0,0 +1,209 @@
+package types
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/app"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// Python build type detected python.
+	Python = "python"
+
+	requirementsTXT = "requirements.txt"
+	setupPy         = "setup.py"
+	setupCfg        = "setup.cfg"
+	pyprojectTOML   = "pyproject.toml"
+	pipfile         = "Pipfile"
+
+	// Pip python project installed with pip.
+	Pip = "pip"
+
+	// Setuptools python project built with setuptools.
+	Setuptools = "setuptools"
+
+	// Poetry python project built with poetry.
+	Poetry = "poetry"
+
+	// Flit python project built with flit.
+	Flit = "flit"
+
+	// Hatch python project built with hatch.
+	Hatch = "hatch"
+
+	// PDM python project built with pdm.
+	PDM = "pdm"
+
+	// Pipenv python project managed with pipenv.
+	Pipenv = "pipenv"
+
+	pythonPriority = 150
+	buildBackend   = "build-system.build-backend"
+	toolPoetry     = "tool.poetry"
+
+	projectRequiresPython = "project.requires-python"
+)
+
+// buildBackends maps the PEP 517 build backend
+// module prefixes to their packaging tool.
+var buildBackends = []struct {
+	prefix string
+	tool   string
+}{
+	{"poetry.", Poetry},
+	{"flit_core.", Flit},
+	{"flit.", Flit},
+	{"hatchling.", Hatch},
+	{"pdm.", PDM},
+	{"setuptools.", Setuptools},
+}
+
+// pythonRequires matches the python_requires
+// option of setup.cfg and setup.py.
+var pythonRequires = regexp.MustCompile(`python_requires\s*=\s*(?:['"]([^'"]+)['"]|([^'"\s][^\n]*))`)
+
+// PythonBuild holds the details
+// of a python project.
+type PythonBuild struct {
+	PackagingTool string
+	BuildBackend  string
+}
+
+// pythonDetector recognizes python projects
+// and reports their packaging tool.
+type pythonDetector struct {
+	Detector
+}
+
+// NewPython will create a buildToolDetector
+// struct with the BuildToolType set
+// to python.
+func NewPython() *app.GoaBuildToolDetector {
+	return &app.GoaBuildToolDetector{
+		BuildToolType: Python,
+	}
+}
+
+// Confidence returns the confidence with which the
+// marker file identifies a python project. The
+// requirements.txt and setup.cfg files are also
+// found in projects not written in python.
+func (p pythonDetector) Confidence(file string) float64 {
+	switch file {
+	case requirementsTXT:
+		return 0.7
+	case setupCfg:
+		return 0.8
+	default:
+		return fullConfidence
+	}
+}
+
+// Describe reports the packaging tool of the project.
+// The build backend declared in pyproject.toml takes
+// precedence over Pipfile, setuptools files and
+// requirements.txt. The python version is read from
+// .python-version, or else from the requires-python
+// or python_requires constraint.
+func (p pythonDetector) Describe(ctx context.Context, src Source, match *Match) error {
+	build := PythonBuild{
+		PackagingTool: packagingToolOf(match.Files),
+	}
+	match.Python = &build
+
+	pythonVersion, err := readVersionFile(ctx, src, pythonVersionFile)
+	if err != nil {
+		return err
+	}
+	requires, err := pythonRequiresOf(ctx, src, match.Files)
+	if err != nil {
+		return err
+	}
+
+	if contains(match.Files, pyprojectTOML) {
+		content, err := src.ReadFile(ctx, pyprojectTOML)
+		if err != nil {
+			return err
+		}
+		pyproject, err := toml.Load(string(content))
+		if err != nil {
+			return err
+		}
+		if requiresPython, ok := pyproject.Get(projectRequiresPython).(string); ok {
+			requires = requiresPython
+		}
+		build.PackagingTool = packagingToolOfPyproject(pyproject, &build)
+	}
+	match.Runtime = newRuntime(PythonRuntime, pythonVersion, requires)
+	return nil
+}
+
+// packagingToolOfPyproject returns the packaging tool
+// of the build backend declared in pyproject.toml,
+// which is recorded in build.
+func packagingToolOfPyproject(pyproject *toml.Tree, build *PythonBuild) string {
+	if backend, ok := pyproject.Get(buildBackend).(string); ok {
+		build.BuildBackend = backend
+		for _, buildBackend := range buildBackends {
+			if strings.HasPrefix(backend, buildBackend.prefix) {
+				return buildBackend.tool
+			}
+		}
+	}
+	if pyproject.Has(toolPoetry) {
+		return Poetry
+	}
+	return build.PackagingTool
+}
+
+// pythonRequiresOf returns the python_requires
+// constraint of setup.cfg, or else of setup.py.
+func pythonRequiresOf(ctx context.Context, src Source, files []string) (string, error) {
+	for _, file := range []string{setupCfg, setupPy} {
+		if !contains(files, file) {
+			continue
+		}
+		content, err := src.ReadFile(ctx, file)
+		if err != nil {
+			return "", err
+		}
+		if found := pythonRequires.FindSubmatch(content); found != nil {
+			return strings.TrimSpace(string(found[1]) + string(found[2])), nil
+		}
+	}
+	return "", nil
+}
+
+// packagingToolOf returns the packaging tool
+// implied by the marker files alone.
+func packagingToolOf(files []string) string {
+	switch {
+	case contains(files, pipfile):
+		return Pipenv
+	case contains(files, setupPy), contains(files, setupCfg), contains(files, pyprojectTOML):
+		return Setuptools
+	default:
+		return Pip
+	}
+}
+
+// contains returns whether the
+// file is part of files.
+func contains(files []string, file string) bool {
+	for _, f := range files {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
+// getTypePython returns the Detector for python.
+func getTypePython() Detector {
+	return pythonDetector{
+		NewDetector(Python, pythonPriority, NewPython, pyprojectTOML, setupPy, setupCfg, pipfile, requirementsTXT),
+	}
+}
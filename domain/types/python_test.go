@@ -0,0 +1,70 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Python", func() {
+	ctx := context.TODO()
+
+	Context("Describe", func() {
+		It("Requirements only", func() {
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "requirements.txt"}})
+			Expect(detection.Describe(ctx, mapSource{})).Should(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("pip"), "packaging tool should be 'pip'")
+		})
+
+		It("Pipfile and requirements", func() {
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "Pipfile"}, {BuildType: Python, File: "requirements.txt"}})
+			Expect(detection.Describe(ctx, mapSource{})).Should(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("pipenv"), "packaging tool should be 'pipenv'")
+		})
+
+		It("Setup script", func() {
+			src := mapSource{"setup.py": "setup(\n    name='app',\n    python_requires='>=3.6, <4',\n)\n"}
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "setup.py"}, {BuildType: Python, File: "requirements.txt"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("setuptools"), "packaging tool should be 'setuptools'")
+			Expect(detection.Top().Runtime).Should(Equal(&Runtime{Name: PythonRuntime, Version: ">=3.6, <4"}), "version should be read from python_requires")
+		})
+
+		It("Poetry build backend", func() {
+			src := mapSource{"pyproject.toml": "[tool.poetry]\nname = \"app\"\n\n[build-system]\nrequires = [\"poetry-core\"]\nbuild-backend = \"poetry.core.masonry.api\"\n"}
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "pyproject.toml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Python).Should(Equal(&PythonBuild{PackagingTool: Poetry, BuildBackend: "poetry.core.masonry.api"}), "packaging tool should be 'poetry'")
+		})
+
+		It("Flit build backend", func() {
+			src := mapSource{"pyproject.toml": "[build-system]\nrequires = [\"flit_core >=3.2,<4\"]\nbuild-backend = \"flit_core.buildapi\"\n"}
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "pyproject.toml"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("flit"), "packaging tool should be 'flit'")
+		})
+
+		It("Hatch build backend", func() {
+			src := mapSource{"pyproject.toml": "[build-system]\nrequires = [\"hatchling\"]\nbuild-backend = \"hatchling.build\"\n"}
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "pyproject.toml"}, {BuildType: Python, File: "requirements.txt"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("hatch"), "packaging tool should be 'hatch'")
+		})
+
+		It("Pyproject without build system", func() {
+			src := mapSource{"pyproject.toml": "[tool.black]\nline-length = 88\n"}
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "pyproject.toml"}, {BuildType: Python, File: "Pipfile"}})
+			Expect(detection.Describe(ctx, src)).Should(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("pipenv"), "packaging tool should be 'pipenv'")
+		})
+
+		It("Unreadable pyproject", func() {
+			detection := NewDetection([]BuildType{{BuildType: Python, File: "pyproject.toml"}})
+			Expect(detection.Describe(ctx, mapSource{})).ShouldNot(BeNil())
+			Expect(detection.Top().Python.PackagingTool).Should(BeEquivalentTo("setuptools"), "packaging tool should fall back to 'setuptools'")
+		})
+	})
+})
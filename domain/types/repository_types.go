@@ -2,6 +2,32 @@ package types
 
 import (
 	"context"
+	"errors"
+
+	"github.com/fabric8-services/build-tool-detector/log"
+)
+
+const (
+	repositoryField = "repository"
+)
+
+var (
+	// ErrFailedContentRetrieval to return if unable to get contents.
+	ErrFailedContentRetrieval = errors.New("unable to retrieve contents")
+
+	// ErrInvalidPath repository url is invalid.
+	ErrInvalidPath = errors.New("url is invalid")
+
+	// ErrResourceNotFound no resource found.
+	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrTruncatedTree the listing of the
+	// repository files is incomplete.
+	ErrTruncatedTree = errors.New("repository tree truncated")
+
+	// ErrFailedDescribe unable to add the
+	// details of the detected build tools.
+	ErrFailedDescribe = errors.New("unable to describe build tools")
 )
 
 // RepositoryService holds information about
@@ -10,5 +36,33 @@ type RepositoryService interface {
 	Owner() string
 	Repository() string
 	Branch() string
-	DetectBuildTool(ctx context.Context) (*string, error)
+	DetectBuildTool(ctx context.Context) (*Detection, error)
+	Contents(ctx context.Context) (*Tree, Revision, error)
+}
+
+// DetectIn gets the contents of the repository and returns
+// the detected build tools, pinned by the override file of
+// the repository if any. Describe errors are only logged.
+// The build tool type is set to Unknown in case of an
+// error, ErrFailedContentRetrieval when nothing matched.
+func DetectIn(ctx context.Context, service RepositoryService, precedence []string) (*Detection, error) {
+	files, revision, err := service.Contents(ctx)
+	if err != nil {
+		return NewDetection(nil), err
+	}
+	files, override, err := ReadOverride(ctx, files)
+	if err != nil {
+		return NewDetection(nil), err
+	}
+
+	detection, err := Detect(ctx, files, precedence)
+	if err != nil {
+		log.Logger().WithError(err).WithField(repositoryField, service.Repository()).Warnf(ErrFailedDescribe.Error())
+	}
+	detection.Revision = revision
+	override.Apply(detection)
+	if len(detection.Matches) == 0 {
+		return detection, ErrFailedContentRetrieval
+	}
+	return detection, nil
 }
@@ -0,0 +1,293 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/fabric8-services/build-tool-detector/app"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Rules is the content of a rules file, declaring
+// detectors without rebuilding the service:
+//
+//	rules:
+//	- name: bazel
+//	  priority: 90
+//	  files: [WORKSPACE, "*.bzl"]
+//	  predicates:
+//	  - regex: 'workspace\(name'
+//	- name: maven
+//	  priority: 300
+//	  files: [pom.xml]
+//	  predicates:
+//	  - xpath: /project/packaging
+//	    value: ^(jar|war)$
+//
+// A rule named after a registered build tool replaces
+// its marker files and priority, keeping its details.
+type Rules struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule declares a build tool recognized by file
+// patterns, matched as by path.Match, whose
+// contents must satisfy every predicate.
+type Rule struct {
+	Name       string      `yaml:"name"`
+	Priority   int         `yaml:"priority"`
+	Files      []string    `yaml:"files"`
+	Predicates []Predicate `yaml:"predicates"`
+}
+
+// Predicate holds on the contents of File, relative to
+// the directory of the marker file, or of the marker
+// file itself if empty, when Regex matches, or when the
+// value selected by JSONPath or XPath exists and matches
+// the Value regex, if any. Exactly one of Regex, JSONPath
+// and XPath must be set, Value only applies to the
+// latter two.
+type Predicate struct {
+	File     string `yaml:"file"`
+	Regex    string `yaml:"regex"`
+	JSONPath string `yaml:"jsonpath"`
+	XPath    string `yaml:"xpath"`
+	Value    string `yaml:"value"`
+}
+
+// RuleError is an invalid rule of a rules file,
+// Index is the position of the rule, from 1.
+type RuleError struct {
+	Index int
+	Name  string
+	Err   error
+}
+
+// Error describes the invalid rule.
+func (e RuleError) Error() string {
+	return fmt.Sprintf("rule %d (%s): %v", e.Index, e.Name, e.Err)
+}
+
+// RulesError holds every invalid
+// rule of a rules file.
+type RulesError []RuleError
+
+// Error describes the invalid rules.
+func (e RulesError) Error() string {
+	messages := make([]string, len(e))
+	for i, ruleErr := range e {
+		messages[i] = ruleErr.Error()
+	}
+	return "invalid detection rules: " + strings.Join(messages, "; ")
+}
+
+// ruleDetector is a Detector declared by a rule,
+// delegating the details to the detector it
+// replaces, if any.
+type ruleDetector struct {
+	Detector
+	base       Detector
+	predicates []predicate
+}
+
+// predicate is a compiled Predicate.
+type predicate struct {
+	file  string
+	match func(content []byte) (bool, error)
+}
+
+// LoadRulesFile reads the rules file and
+// registers the detectors it declares.
+func LoadRulesFile(file string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return LoadRules(content)
+}
+
+// LoadRules validates the rules and registers the
+// detectors they declare. Nothing is registered
+// unless every rule is valid, invalid rules are
+// reported as a RulesError.
+func LoadRules(content []byte) error {
+	var rules Rules
+	if err := yaml.UnmarshalStrict(content, &rules); err != nil {
+		return err
+	}
+
+	var errs RulesError
+	detectors := make([]Detector, 0, len(rules.Rules))
+	names := make(map[string]bool)
+	for i, rule := range rules.Rules {
+		detector, err := newRuleDetector(rule, names)
+		if err != nil {
+			errs = append(errs, RuleError{Index: i + 1, Name: rule.Name, Err: err})
+			continue
+		}
+		detectors = append(detectors, detector)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	for _, detector := range detectors {
+		registry.detectors[detector.Name()] = detector
+	}
+	return nil
+}
+
+// newRuleDetector validates the rule and
+// creates the detector it declares.
+func newRuleDetector(rule Rule, names map[string]bool) (Detector, error) {
+	name := strings.TrimSpace(rule.Name)
+	switch {
+	case name == "":
+		return nil, errors.New("name is missing")
+	case names[name]:
+		return nil, errors.New("name is declared by a previous rule")
+	case len(rule.Files) == 0:
+		return nil, errors.New("files are missing")
+	case rule.Priority < 0:
+		return nil, fmt.Errorf("priority %d is negative", rule.Priority)
+	}
+	names[name] = true
+
+	for _, file := range rule.Files {
+		if _, err := path.Match(file, ""); err != nil || strings.TrimSpace(file) == "" {
+			return nil, fmt.Errorf("file pattern %q is invalid", file)
+		}
+	}
+	predicates := make([]predicate, len(rule.Predicates))
+	for i, p := range rule.Predicates {
+		compiled, err := compilePredicate(p)
+		if err != nil {
+			return nil, fmt.Errorf("predicate %d: %v", i+1, err)
+		}
+		predicates[i] = compiled
+	}
+
+	base, _ := Lookup(name)
+	if previous, ok := base.(ruleDetector); ok {
+		base = previous.base
+	}
+	var constructor func() *app.GoaBuildToolDetector
+	if base != nil {
+		constructor = base.New
+	}
+	return ruleDetector{
+		Detector:   NewDetector(name, rule.Priority, constructor, rule.Files...),
+		base:       base,
+		predicates: predicates,
+	}, nil
+}
+
+// compilePredicate validates the predicate and
+// compiles its regular expressions and path.
+func compilePredicate(p Predicate) (predicate, error) {
+	set := 0
+	for _, value := range []string{p.Regex, p.JSONPath, p.XPath} {
+		if value != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return predicate{}, errors.New("exactly one of regex, jsonpath and xpath must be set")
+	}
+	if p.Regex != "" && p.Value != "" {
+		return predicate{}, errors.New("value only applies to jsonpath and xpath")
+	}
+
+	var value *regexp.Regexp
+	if p.Value != "" {
+		var err error
+		if value, err = regexp.Compile(p.Value); err != nil {
+			return predicate{}, fmt.Errorf("value %q is invalid: %v", p.Value, err)
+		}
+	}
+
+	compiled := predicate{file: p.File}
+	switch {
+	case p.Regex != "":
+		regex, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return predicate{}, fmt.Errorf("regex %q is invalid: %v", p.Regex, err)
+		}
+		compiled.match = func(content []byte) (bool, error) {
+			return regex.Match(content), nil
+		}
+	case p.JSONPath != "":
+		selector, err := parseJSONPath(p.JSONPath)
+		if err != nil {
+			return predicate{}, fmt.Errorf("jsonpath %q is invalid: %v", p.JSONPath, err)
+		}
+		compiled.match = func(content []byte) (bool, error) {
+			return selector.match(content, value)
+		}
+	default:
+		selector, err := parseXPath(p.XPath)
+		if err != nil {
+			return predicate{}, fmt.Errorf("xpath %q is invalid: %v", p.XPath, err)
+		}
+		compiled.match = func(content []byte) (bool, error) {
+			return selector.match(content, value)
+		}
+	}
+	return compiled, nil
+}
+
+// Verify returns whether the contents satisfy every
+// predicate. Files missing from the repository do
+// not satisfy predicates.
+func (r ruleDetector) Verify(ctx context.Context, src Source, file string) (bool, error) {
+	for _, p := range r.predicates {
+		target := file
+		if p.file != "" {
+			target = path.Join(path.Dir(file), p.file)
+		}
+		content, err := src.ReadFile(ctx, target)
+		if err == ErrFileNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if ok, err := p.match(content); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Confidence delegates to the replaced
+// detector, if it implements Weigher.
+func (r ruleDetector) Confidence(file string) float64 {
+	if weigher, ok := r.base.(Weigher); ok {
+		return weigher.Confidence(file)
+	}
+	return fullConfidence
+}
+
+// Describe delegates to the replaced detector, if it
+// implements Describer. Rules named after a runtime
+// without detector, such as ruby, report its version.
+func (r ruleDetector) Describe(ctx context.Context, src Source, match *Match) error {
+	if describer, ok := r.base.(Describer); ok {
+		return describer.Describe(ctx, src, match)
+	}
+	if read, ok := runtimeReaders[r.Name()]; ok {
+		runtime, err := read(ctx, src)
+		if err != nil {
+			return err
+		}
+		match.Runtime = runtime
+	}
+	return nil
+}
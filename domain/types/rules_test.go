@@ -0,0 +1,126 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Rules", func() {
+	ctx := context.TODO()
+	var restore func()
+
+	BeforeEach(func() {
+		restore = SnapshotRegistry()
+	})
+	AfterEach(func() {
+		restore()
+	})
+
+	It("Declared build tool", func() {
+		Expect(LoadRules([]byte(`
+rules:
+- name: bazel
+  priority: 5
+  files: [WORKSPACE, "*.bzl"]
+  predicates:
+  - regex: 'workspace\(name'
+- name: dotnet
+  priority: 4
+  files: ["*.csproj"]
+  predicates:
+  - xpath: /Project/PropertyGroup/TargetFramework
+    value: ^netcoreapp
+- name: deno
+  priority: 3
+  files: [deno.json]
+  predicates:
+  - jsonpath: $.tasks['start']
+`))).Should(BeNil())
+
+		tree := NewTree([]Entry{{Path: "WORKSPACE"}, {Path: "rules.bzl"}, {Path: "app.csproj"}, {Path: "deno.json"}}, func(ctx context.Context, path string) ([]byte, error) {
+			switch path {
+			case "WORKSPACE":
+				return []byte(`workspace(name = "app")`), nil
+			case "app.csproj":
+				return []byte(`<Project><PropertyGroup><TargetFramework>net472</TargetFramework></PropertyGroup></Project>`), nil
+			case "deno.json":
+				return []byte(`{"tasks": {"start": "deno run main.ts"}}`), nil
+			}
+			return nil, nil
+		})
+		detection, err := Detect(ctx, tree, nil)
+		Expect(err).Should(BeNil())
+		Expect(detection.Matches).Should(HaveLen(2), "dotnet should not satisfy its predicate")
+		Expect(detection.Matches[0]).Should(Equal(Match{BuildType: "bazel", Confidence: 1, Evidence: "WORKSPACE", Files: []string{"WORKSPACE"}}), "rules.bzl should not satisfy the predicate")
+		Expect(detection.Matches[1].BuildType).Should(Equal("deno"), "deno should be detected from the json path")
+		Expect(New("bazel").BuildToolType).Should(Equal("bazel"), "response should name the declared build tool")
+	})
+
+	It("Predicate file relative to the marker file", func() {
+		Expect(LoadRules([]byte(`
+rules:
+- name: helm
+  files: ["charts/*/Chart.yaml"]
+  predicates:
+  - file: values.yaml
+    regex: 'image:'
+`))).Should(BeNil())
+
+		tree := NewTree([]Entry{{Path: "charts/app/Chart.yaml"}, {Path: "charts/app/values.yaml"}, {Path: "charts/lib/Chart.yaml"}}, func(ctx context.Context, path string) ([]byte, error) {
+			if path == "charts/app/values.yaml" {
+				return []byte("image: app:latest\n"), nil
+			}
+			return []byte("name: chart\n"), nil
+		})
+		detection, err := Detect(ctx, tree, nil)
+		Expect(err).Should(BeNil())
+		Expect(detection.Matches).Should(HaveLen(1), "helm should be detected")
+		Expect(detection.Top().Files).Should(Equal([]string{"charts/app/Chart.yaml"}), "values.yaml should be read next to the marker file")
+	})
+
+	It("Replaced build tool", func() {
+		Expect(LoadRules([]byte("rules:\n- name: golang\n  priority: 100\n  files: [go.mod, go.work]\n"))).Should(BeNil())
+
+		detection := NewDetection([]BuildType{{BuildType: Golang, File: "go.work"}})
+		Expect(detection.Describe(ctx, mapSource{})).Should(BeNil())
+		Expect(detection.Top().Golang).ShouldNot(BeNil(), "the replaced detector should describe the build")
+		Expect(detection.Top().Confidence).Should(BeNumerically("<", 1), "the replaced detector should weigh the marker files")
+	})
+
+	It("Invalid rules", func() {
+		err := LoadRules([]byte(`
+rules:
+- name: valid
+  files: [valid.txt]
+- files: [file]
+- name: broken
+  files: ["[", ok]
+  predicates:
+  - regex: "("
+- name: selectors
+  files: [file]
+  predicates:
+  - jsonpath: .missing.root
+  - xpath: //anywhere
+- name: ignored
+  files: [file]
+  predicates:
+  - regex: version
+    value: ^1
+`))
+		Expect(err).Should(BeAssignableToTypeOf(RulesError{}))
+		Expect(err.(RulesError)).Should(HaveLen(4), "every invalid rule should be reported")
+		Expect(err.Error()).Should(ContainSubstring(`rule 2 (): name is missing`))
+		Expect(err.Error()).Should(ContainSubstring(`rule 3 (broken): file pattern "[" is invalid`))
+		Expect(err.Error()).Should(ContainSubstring(`rule 4 (selectors): predicate 1: jsonpath ".missing.root" is invalid`))
+		Expect(err.Error()).Should(ContainSubstring(`rule 5 (ignored): predicate 1: value only applies to jsonpath and xpath`))
+		_, ok := Lookup("valid")
+		Expect(ok).Should(BeFalse(), "nothing should be registered when a rule is invalid")
+
+		Expect(LoadRules([]byte("rules:\n- name: x\n  unknown: true\n"))).ShouldNot(BeNil(), "unknown fields should be rejected")
+	})
+})
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// JavaRuntime builds running on the jvm.
+	JavaRuntime = "java"
+
+	// NodeRuntime builds running on node.
+	NodeRuntime = "node"
+
+	// GoRuntime builds compiled with go.
+	GoRuntime = "go"
+
+	// PythonRuntime builds running on python.
+	PythonRuntime = "python"
+
+	// RubyRuntime builds running on ruby.
+	RubyRuntime = "ruby"
+
+	// RustRuntime builds compiled with rust.
+	RustRuntime = "rust"
+
+	javaVersionFile   = ".java-version"
+	nvmrc             = ".nvmrc"
+	nodeVersionFile   = ".node-version"
+	pythonVersionFile = ".python-version"
+	rubyVersionFile   = ".ruby-version"
+	gemfile           = "Gemfile"
+	rustToolchain     = "rust-toolchain"
+	rustToolchainTOML = "rust-toolchain.toml"
+	cargoTOML         = "Cargo.toml"
+	toolchainChannel  = "toolchain.channel"
+	cargoRustVersion  = "package.rust-version"
+	versionPrefix     = "v"
+	commentPrefix     = "#"
+)
+
+// gemfileRuby matches the ruby
+// directive of a Gemfile.
+var gemfileRuby = regexp.MustCompile(`(?m)^\s*ruby\s*\(?\s*['"]([^'"]+)['"]`)
+
+// runtimeReaders read the runtime of the build
+// tools which are only declared by rules,
+// by name of the rule.
+var runtimeReaders = map[string]func(ctx context.Context, src Source) (*Runtime, error){
+	RubyRuntime: readRubyRuntime,
+	RustRuntime: readRustRuntime,
+}
+
+// Runtime is the language runtime a build requires.
+// Version is the version, or the version constraint,
+// declared by the project, empty if none is.
+type Runtime struct {
+	Name    string
+	Version string
+}
+
+// newRuntime creates the runtime with
+// the first version which is not empty.
+func newRuntime(name string, versions ...string) *Runtime {
+	runtime := Runtime{Name: name}
+	for _, version := range versions {
+		if version = strings.TrimSpace(version); version != "" {
+			runtime.Version = version
+			break
+		}
+	}
+	return &runtime
+}
+
+// readVersionFile returns the version held by the
+// first of the version files present, such as
+// .nvmrc or .ruby-version. Comments and blank
+// lines are skipped, along with a leading v.
+func readVersionFile(ctx context.Context, src Source, files ...string) (string, error) {
+	for _, file := range files {
+		content, err := src.ReadFile(ctx, file)
+		if err == ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return firstLineOf(content), nil
+	}
+	return "", nil
+}
+
+// firstLineOf returns the first line of the
+// content which is neither blank nor a comment.
+func firstLineOf(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+		if strings.HasPrefix(line, versionPrefix) && len(line) > 1 && line[1] >= '0' && line[1] <= '9' {
+			line = line[1:]
+		}
+		return line
+	}
+	return ""
+}
+
+// readRubyRuntime reads the ruby version from
+// .ruby-version or else from the Gemfile.
+func readRubyRuntime(ctx context.Context, src Source) (*Runtime, error) {
+	rubyVersion, err := readVersionFile(ctx, src, rubyVersionFile)
+	if err != nil {
+		return nil, err
+	}
+	content, err := src.ReadFile(ctx, gemfile)
+	if err != nil && err != ErrFileNotFound {
+		return nil, err
+	}
+	var gemfileVersion string
+	if found := gemfileRuby.FindSubmatch(content); found != nil {
+		gemfileVersion = string(found[1])
+	}
+	return newRuntime(RubyRuntime, rubyVersion, gemfileVersion), nil
+}
+
+// readRustRuntime reads the rust toolchain from
+// rust-toolchain.toml or rust-toolchain, either
+// holding the channel alone or as toml, or else
+// from the rust-version of Cargo.toml.
+func readRustRuntime(ctx context.Context, src Source) (*Runtime, error) {
+	var toolchain string
+	for _, file := range []string{rustToolchainTOML, rustToolchain} {
+		content, err := src.ReadFile(ctx, file)
+		if err == ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		toolchain = firstLineOf(content)
+		if tree, err := toml.Load(string(content)); err == nil && tree.Has(toolchainChannel) {
+			toolchain, _ = tree.Get(toolchainChannel).(string)
+		}
+		break
+	}
+
+	var rustVersion string
+	content, err := src.ReadFile(ctx, cargoTOML)
+	if err != nil && err != ErrFileNotFound {
+		return nil, err
+	}
+	if cargo, err := toml.Load(string(content)); err == nil {
+		rustVersion, _ = cargo.Get(cargoRustVersion).(string)
+	}
+	return newRuntime(RustRuntime, toolchain, rustVersion), nil
+}
@@ -0,0 +1,95 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Runtime", func() {
+	ctx := context.TODO()
+
+	describe := func(buildType string, file string, src mapSource) *Runtime {
+		detection := NewDetection([]BuildType{{BuildType: buildType, File: file}})
+		Expect(detection.Describe(ctx, src)).Should(BeNil())
+		return detection.Top().Runtime
+	}
+
+	It("Maven compiler properties", func() {
+		runtime := describe(Maven, "pom.xml", mapSource{"pom.xml": `<project>
+			<artifactId>app</artifactId>
+			<properties><java.version>11</java.version><maven.compiler.source>${java.version}</maven.compiler.source></properties>
+		</project>`})
+		Expect(runtime).Should(Equal(&Runtime{Name: JavaRuntime, Version: "11"}), "version should be resolved from the compiler source")
+
+		runtime = describe(Maven, "pom.xml", mapSource{"pom.xml": "<project><artifactId>app</artifactId></project>", ".java-version": "1.8\n"})
+		Expect(runtime).Should(Equal(&Runtime{Name: JavaRuntime, Version: "1.8"}), "version should be read from .java-version")
+	})
+
+	It("Gradle toolchain", func() {
+		runtime := describe(Gradle, "build.gradle", mapSource{"build.gradle": "java {\n  sourceCompatibility = JavaVersion.VERSION_1_8\n}"})
+		Expect(runtime).Should(Equal(&Runtime{Name: JavaRuntime, Version: "1.8"}), "version should be read from the source compatibility")
+
+		runtime = describe(Gradle, "build.gradle.kts", mapSource{"build.gradle.kts": "java { toolchain { languageVersion.set(JavaLanguageVersion.of(17)) } }"})
+		Expect(runtime).Should(Equal(&Runtime{Name: JavaRuntime, Version: "17"}), "version should be read from the toolchain")
+	})
+
+	It("Node version files", func() {
+		runtime := describe(NodeJS, "package.json", mapSource{"package.json": `{"engines": {"node": ">=10"}}`, ".nvmrc": "# lts\nv12.16.1\n"})
+		Expect(runtime).Should(Equal(&Runtime{Name: NodeRuntime, Version: "12.16.1"}), ".nvmrc should take precedence over engines")
+
+		runtime = describe(NodeJS, "package.json", mapSource{"package.json": `{"engines": {"node": ">=10"}}`})
+		Expect(runtime).Should(Equal(&Runtime{Name: NodeRuntime, Version: ">=10"}), "version should be read from engines")
+	})
+
+	It("Go directive", func() {
+		runtime := describe(Golang, "go.mod", mapSource{"go.mod": "module example.com/app\n\ngo 1.12\n"})
+		Expect(runtime).Should(Equal(&Runtime{Name: GoRuntime, Version: "1.12"}), "version should be read from go.mod")
+	})
+
+	It("Python version file", func() {
+		runtime := describe(Python, "pyproject.toml", mapSource{"pyproject.toml": "[project]\nrequires-python = \">=3.8\"\n", ".python-version": "3.9.1"})
+		Expect(runtime).Should(Equal(&Runtime{Name: PythonRuntime, Version: "3.9.1"}), ".python-version should take precedence")
+
+		runtime = describe(Python, "setup.cfg", mapSource{"setup.cfg": "[options]\npython_requires = >=3.6\n"})
+		Expect(runtime).Should(Equal(&Runtime{Name: PythonRuntime, Version: ">=3.6"}), "version should be read from setup.cfg")
+	})
+
+	Context("Rules", func() {
+		var restore func()
+		BeforeEach(func() {
+			restore = SnapshotRegistry()
+			Expect(LoadRules([]byte("rules:\n- name: ruby\n  files: [Gemfile]\n- name: rust\n  files: [Cargo.toml]\n"))).Should(BeNil())
+		})
+		AfterEach(func() {
+			restore()
+		})
+
+		It("Ruby version", func() {
+			runtime := describe("ruby", "Gemfile", mapSource{"Gemfile": "source 'https://rubygems.org'\nruby '2.6.3'\n"})
+			Expect(runtime).Should(Equal(&Runtime{Name: RubyRuntime, Version: "2.6.3"}), "version should be read from the Gemfile")
+
+			runtime = describe("ruby", "Gemfile", mapSource{"Gemfile": "source 'https://rubygems.org'\n", ".ruby-version": "2.7.0\n"})
+			Expect(runtime).Should(Equal(&Runtime{Name: RubyRuntime, Version: "2.7.0"}), "version should be read from .ruby-version")
+		})
+
+		It("Rust toolchain", func() {
+			runtime := describe("rust", "Cargo.toml", mapSource{"Cargo.toml": "[package]\nname = \"app\"\nrust-version = \"1.56\"\n", "rust-toolchain": "nightly-2020-07-10\n"})
+			Expect(runtime).Should(Equal(&Runtime{Name: RustRuntime, Version: "nightly-2020-07-10"}), "version should be read from rust-toolchain")
+
+			runtime = describe("rust", "Cargo.toml", mapSource{"Cargo.toml": "[package]\nname = \"app\"\n", "rust-toolchain.toml": "[toolchain]\nchannel = \"1.70.0\"\n"})
+			Expect(runtime).Should(Equal(&Runtime{Name: RustRuntime, Version: "1.70.0"}), "version should be read from rust-toolchain.toml")
+
+			runtime = describe("rust", "Cargo.toml", mapSource{"Cargo.toml": "[package]\nname = \"app\"\nrust-version = \"1.56\"\n"})
+			Expect(runtime).Should(Equal(&Runtime{Name: RustRuntime, Version: "1.56"}), "version should be read from Cargo.toml")
+		})
+	})
+
+	It("No version", func() {
+		runtime := describe(Golang, "main.go", mapSource{})
+		Expect(runtime).Should(Equal(&Runtime{Name: GoRuntime}), "runtime should be reported without version")
+	})
+})
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+const (
+	// DefaultScanDepth is the depth of the directories
+	// scanned when no depth is requested, the root of
+	// the repository is at depth 0.
+	DefaultScanDepth = 3
+)
+
+// ignoredDirs are the directories, and their
+// subdirectories, which are not build roots
+// even if they contain marker files.
+var ignoredDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"testdata":     true,
+	"fixtures":     true,
+	"__fixtures__": true,
+}
+
+// Root is a directory of a repository which is
+// the root of a build. Path and Evidence are
+// relative to the root of the repository.
+type Root struct {
+	Path      string
+	BuildType string
+	Evidence  string
+}
+
+// Scan walks the directories of the tree down to depth
+// and returns every directory containing marker files,
+// along with the build tool of highest precedence.
+// Dependency, fixture and hidden directories are
+// skipped. The marker files must satisfy the
+// predicates of detectors implementing Verifier,
+// as when detecting, which are the only files
+// fetched. The first verify error is returned.
+func Scan(ctx context.Context, tree *Tree, depth int, precedence []string) ([]Root, error) {
+	buildTypes := GetTypesByPrecedence(precedence)
+
+	var roots []Root
+	for _, dir := range append([]string{root}, tree.Paths()...) {
+		if dir != root && (!tree.entries[dir] || isIgnored(dir) || depthOf(dir) > depth) {
+			continue
+		}
+
+		var found []BuildType
+		for _, buildType := range buildTypes {
+			for _, file := range tree.Glob(path.Join(dir, buildType.File)) {
+				ok, err := verify(ctx, tree, BuildType{BuildType: buildType.BuildType, File: file})
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				if dir != root {
+					file = strings.TrimPrefix(file, dir+"/")
+				}
+				found = append(found, BuildType{BuildType: buildType.BuildType, File: file})
+			}
+		}
+		top := NewDetection(found).Top()
+		if top == nil {
+			continue
+		}
+		roots = append(roots, Root{
+			Path:      path.Join(root, tree.dir, dir),
+			BuildType: top.BuildType,
+			Evidence:  path.Join(tree.dir, dir, top.Evidence),
+		})
+	}
+	return roots, nil
+}
+
+// isIgnored returns whether the directory, or
+// one of its parents, is not to be scanned.
+func isIgnored(dir string) bool {
+	for _, name := range strings.Split(dir, "/") {
+		if ignoredDirs[name] || strings.HasPrefix(name, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// depthOf returns the depth of the
+// directory below the root.
+func depthOf(dir string) int {
+	return strings.Count(dir, "/") + 1
+}
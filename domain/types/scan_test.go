@@ -0,0 +1,90 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Scan", func() {
+	ctx := context.TODO()
+	var tree *Tree
+
+	BeforeEach(func() {
+		tree = NewTree([]Entry{
+			{Path: "pom.xml"},
+			{Path: "backend/pom.xml"},
+			{Path: "frontend/package.json"},
+			{Path: "frontend/node_modules/left-pad/package.json"},
+			{Path: "services/api/go.mod"},
+			{Path: "services/api/cmd/server/main.go"},
+			{Path: "services/api/vendor/github.com/pkg/errors/main.go"},
+			{Path: "services/api/testdata/pom.xml"},
+			{Path: ".github/package.json"},
+			{Path: "docs/README.md"},
+		}, nil)
+	})
+
+	It("Build roots", func() {
+		roots, err := Scan(ctx, tree, DefaultScanDepth, nil)
+		Expect(err).Should(BeNil())
+		Expect(roots).Should(Equal([]Root{
+			{Path: ".", BuildType: Maven, Evidence: "pom.xml"},
+			{Path: "backend", BuildType: Maven, Evidence: "backend/pom.xml"},
+			{Path: "frontend", BuildType: NodeJS, Evidence: "frontend/package.json"},
+			{Path: "services/api", BuildType: Golang, Evidence: "services/api/go.mod"},
+		}), "dependency, fixture, hidden and deeper directories should be skipped")
+	})
+
+	It("Depth limit", func() {
+		roots, err := Scan(ctx, tree, 1, nil)
+		Expect(err).Should(BeNil())
+		Expect(roots).Should(HaveLen(3), "only the root and its directories should be scanned")
+		Expect(roots[2].Path).Should(Equal("frontend"), "'services/api' should be too deep")
+
+		roots, err = Scan(ctx, tree, 4, nil)
+		Expect(err).Should(BeNil())
+		Expect(roots[4].Path).Should(Equal("services/api/cmd/server"), "'services/api/cmd/server' should be scanned")
+	})
+
+	It("Subdirectory", func() {
+		sub, err := tree.Sub("services")
+		Expect(err).Should(BeNil())
+		roots, err := Scan(ctx, sub, 1, nil)
+		Expect(err).Should(BeNil())
+		Expect(roots).Should(Equal([]Root{{Path: "services/api", BuildType: Golang, Evidence: "services/api/go.mod"}}), "paths should be relative to the repository")
+	})
+
+	Context("Rules", func() {
+		var restore func()
+		BeforeEach(func() {
+			restore = SnapshotRegistry()
+		})
+		AfterEach(func() {
+			restore()
+		})
+
+		It("Predicates", func() {
+			Expect(LoadRules([]byte("rules:\n- name: maven\n  priority: 300\n  files: [pom.xml]\n  predicates:\n  - xpath: /project/packaging\n    value: ^war$\n"))).Should(BeNil())
+			tree := NewTree([]Entry{{Path: "pom.xml"}, {Path: "web/pom.xml"}}, func(ctx context.Context, path string) ([]byte, error) {
+				if path == "web/pom.xml" {
+					return []byte("<project><packaging>war</packaging></project>"), nil
+				}
+				return []byte("<project><packaging>pom</packaging></project>"), nil
+			})
+			roots, err := Scan(ctx, tree, DefaultScanDepth, nil)
+			Expect(err).Should(BeNil())
+			Expect(roots).Should(Equal([]Root{{Path: "web", BuildType: Maven, Evidence: "web/pom.xml"}}), "marker files should satisfy the predicates")
+
+			failing := NewTree([]Entry{{Path: "pom.xml"}}, func(ctx context.Context, path string) ([]byte, error) {
+				return nil, errors.New("unavailable")
+			})
+			_, err = Scan(ctx, failing, DefaultScanDepth, nil)
+			Expect(err).Should(MatchError("unavailable"))
+		})
+	})
+})
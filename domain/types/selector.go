@@ -0,0 +1,152 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonPathRoot  = "$"
+	xpathWildcard = "*"
+)
+
+// jsonPathStep matches a step of a json path,
+// a .key, a ['key'] or an [index].
+var jsonPathStep = regexp.MustCompile(`^(?:\.([^.\[\]]+)|\[['"]([^'"]+)['"]\]|\[(\d+)\])`)
+
+// jsonSelector selects a value of a json document,
+// steps are object keys or array indexes.
+type jsonSelector []interface{}
+
+// parseJSONPath parses the subset of JSONPath made
+// of keys and indexes, e.g. $.engines.node or
+// $.workspaces[0].
+func parseJSONPath(expression string) (jsonSelector, error) {
+	if !strings.HasPrefix(expression, jsonPathRoot) {
+		return nil, fmt.Errorf("must start with %s", jsonPathRoot)
+	}
+	var selector jsonSelector
+	for rest := strings.TrimPrefix(expression, jsonPathRoot); rest != ""; {
+		step := jsonPathStep.FindStringSubmatch(rest)
+		if step == nil {
+			return nil, fmt.Errorf("unsupported step %q", rest)
+		}
+		switch {
+		case step[3] != "":
+			index, _ := strconv.Atoi(step[3])
+			selector = append(selector, index)
+		default:
+			selector = append(selector, step[1]+step[2])
+		}
+		rest = rest[len(step[0]):]
+	}
+	return selector, nil
+}
+
+// match returns whether the selected value exists
+// and, unless value is nil, matches value. Values
+// which are not strings are matched as json.
+func (s jsonSelector) match(content []byte, value *regexp.Regexp) (bool, error) {
+	var document interface{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return false, err
+	}
+	for _, step := range s {
+		switch key := step.(type) {
+		case string:
+			object, ok := document.(map[string]interface{})
+			if document, ok = object[key]; !ok {
+				return false, nil
+			}
+		case int:
+			array, ok := document.([]interface{})
+			if !ok || key >= len(array) {
+				return false, nil
+			}
+			document = array[key]
+		}
+	}
+	if value == nil {
+		return true, nil
+	}
+	if text, ok := document.(string); ok {
+		return value.MatchString(text), nil
+	}
+	text, err := json.Marshal(document)
+	if err != nil {
+		return false, err
+	}
+	return value.Match(text), nil
+}
+
+// xmlSelector selects elements of an xml document by
+// their local names, from the document element.
+type xmlSelector []string
+
+// parseXPath parses the subset of XPath made of
+// absolute element paths, e.g. /project/packaging,
+// where * matches any element.
+func parseXPath(expression string) (xmlSelector, error) {
+	if !strings.HasPrefix(expression, "/") || strings.HasPrefix(expression, "//") {
+		return nil, errors.New("must be an absolute path")
+	}
+	selector := xmlSelector(strings.Split(strings.TrimPrefix(expression, "/"), "/"))
+	for _, name := range selector {
+		if name == "" || strings.ContainsAny(name, "[]()@:=") {
+			return nil, fmt.Errorf("unsupported step %q", name)
+		}
+	}
+	return selector, nil
+}
+
+// match returns whether an element is selected and,
+// unless value is nil, whether the text of one of
+// the selected elements matches value.
+func (s xmlSelector) match(content []byte, value *regexp.Regexp) (bool, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var stack []string
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, element.Name.Local)
+			text.Reset()
+		case xml.CharData:
+			text.Write(element)
+		case xml.EndElement:
+			if s.selects(stack) && (value == nil || value.MatchString(strings.TrimSpace(text.String()))) {
+				return true, nil
+			}
+			stack = stack[:len(stack)-1]
+			text.Reset()
+		}
+	}
+}
+
+// selects returns whether the
+// element path is selected.
+func (s xmlSelector) selects(stack []string) bool {
+	if len(stack) != len(s) {
+		return false
+	}
+	for i, name := range s {
+		if name != xpathWildcard && name != stack[i] {
+			return false
+		}
+	}
+	return true
+}
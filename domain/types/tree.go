@@ -0,0 +1,176 @@
+package types
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	root = "."
+)
+
+// FetchFunc returns the contents of the
+// file at path within a repository.
+type FetchFunc func(ctx context.Context, path string) ([]byte, error)
+
+// Tree is a Source backed by the listing of all files
+// of a repository. File contents are only fetched when
+// a detector needs to inspect them, detectors matching
+// on marker files are evaluated against the listing.
+type Tree struct {
+	entries  map[string]bool
+	paths    []string
+	children map[string][]string
+	fetch    FetchFunc
+	dir      string
+	repo     *Tree
+}
+
+// NewTree creates a Tree from the listing of a repository.
+// Parent directories missing from the listing are added.
+func NewTree(entries []Entry, fetch FetchFunc) *Tree {
+	tree := Tree{
+		entries: make(map[string]bool, len(entries)),
+		fetch:   fetch,
+	}
+	for _, entry := range entries {
+		entryPath := path.Clean(strings.Trim(entry.Path, "/"))
+		if entryPath == root {
+			continue
+		}
+		tree.entries[entryPath] = tree.entries[entryPath] || entry.Dir
+		for dir := path.Dir(entryPath); dir != root; dir = path.Dir(dir) {
+			tree.entries[dir] = true
+		}
+	}
+	tree.index()
+	return &tree
+}
+
+// HasFile returns whether the file
+// is present in the tree.
+func (t *Tree) HasFile(file string) bool {
+	dir, ok := t.entries[path.Clean(file)]
+	return ok && !dir
+}
+
+// Glob returns the files matching the pattern, sorted.
+// Patterns are matched as by path.Match, a pattern
+// without pattern characters is matched as by HasFile.
+func (t *Tree) Glob(pattern string) []string {
+	pattern = path.Clean(pattern)
+	if !strings.ContainsAny(pattern, globMeta) {
+		if t.HasFile(pattern) {
+			return []string{pattern}
+		}
+		return nil
+	}
+
+	var files []string
+	for _, entryPath := range t.paths {
+		if ok, _ := path.Match(pattern, entryPath); ok && !t.entries[entryPath] {
+			files = append(files, entryPath)
+		}
+	}
+	return files
+}
+
+// Paths returns the paths of all
+// files and directories, sorted.
+func (t *Tree) Paths() []string {
+	return append([]string(nil), t.paths...)
+}
+
+// ReadFile fetches the contents of the file. Files
+// missing from the tree result in ErrFileNotFound
+// without fetching.
+func (t *Tree) ReadFile(ctx context.Context, file string) ([]byte, error) {
+	if !t.HasFile(file) {
+		return nil, ErrFileNotFound
+	}
+	return t.fetch(ctx, path.Clean(file))
+}
+
+// ReadDir returns the entries of the directory,
+// sorted by path. The root directory is ".".
+func (t *Tree) ReadDir(ctx context.Context, dir string) ([]Entry, error) {
+	dir = path.Clean(dir)
+	if isDir, ok := t.entries[dir]; dir != root && (!ok || !isDir) {
+		return nil, ErrFileNotFound
+	}
+
+	var entries []Entry
+	for _, entryPath := range t.children[dir] {
+		entries = append(entries, Entry{Path: entryPath, Dir: t.entries[entryPath]})
+	}
+	return entries, nil
+}
+
+// Sub returns the tree rooted at the directory, paths
+// of the returned tree are relative to it. A file is
+// resolved to the directory containing it. Directories
+// missing from the tree result in ErrResourceNotFound.
+func (t *Tree) Sub(dir string) (*Tree, error) {
+	dir = path.Clean(strings.Trim(dir, "/"))
+	if isDir, ok := t.entries[dir]; ok && !isDir {
+		dir = path.Dir(dir)
+	}
+	if dir == root {
+		return t, nil
+	}
+	if isDir, ok := t.entries[dir]; !ok || !isDir {
+		return nil, ErrResourceNotFound
+	}
+
+	sub := Tree{
+		entries: make(map[string]bool),
+		fetch: func(ctx context.Context, file string) ([]byte, error) {
+			return t.fetch(ctx, path.Join(dir, file))
+		},
+		dir:  path.Join(t.dir, dir),
+		repo: t.Root(),
+	}
+	prefix := dir + "/"
+	for entryPath, isDir := range t.entries {
+		if strings.HasPrefix(entryPath, prefix) {
+			sub.entries[strings.TrimPrefix(entryPath, prefix)] = isDir
+		}
+	}
+	sub.index()
+	return &sub, nil
+}
+
+// index sorts the paths of the tree, and
+// the entries of each directory, once the
+// tree is built.
+func (t *Tree) index() {
+	t.paths = make([]string, 0, len(t.entries))
+	for entryPath := range t.entries {
+		t.paths = append(t.paths, entryPath)
+	}
+	sort.Strings(t.paths)
+
+	t.children = make(map[string][]string)
+	for _, entryPath := range t.paths {
+		dir := path.Dir(entryPath)
+		t.children[dir] = append(t.children[dir], entryPath)
+	}
+}
+
+// Root returns the tree of the
+// root of the repository.
+func (t *Tree) Root() *Tree {
+	if t.repo == nil {
+		return t
+	}
+	return t.repo
+}
+
+// Dir returns the directory the tree is rooted
+// at, relative to the root of the repository.
+// It is empty for the root of the repository.
+func (t *Tree) Dir() string {
+	return t.dir
+}
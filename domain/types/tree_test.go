@@ -0,0 +1,98 @@
+package types_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fabric8-services/build-tool-detector/domain/types"
+)
+
+var _ = Describe("Tree", func() {
+	ctx := context.TODO()
+
+	Context("NewTree", func() {
+		var fetched []string
+		var tree *Tree
+
+		BeforeEach(func() {
+			fetched = nil
+			tree = NewTree([]Entry{
+				{Path: "pom.xml"},
+				{Path: "package.json"},
+				{Path: "frontend/src/index.js"},
+				{Path: "docs", Dir: true},
+			}, func(ctx context.Context, path string) ([]byte, error) {
+				fetched = append(fetched, path)
+				switch path {
+				case "pom.xml":
+					return []byte("<project><artifactId>app</artifactId></project>"), nil
+				case "package.json":
+					return []byte(`{"name": "app"}`), nil
+				}
+				return []byte(path), nil
+			})
+		})
+
+		It("Parent directories", func() {
+			Expect(tree.Paths()).Should(Equal([]string{"docs", "frontend", "frontend/src", "frontend/src/index.js", "package.json", "pom.xml"}), "parent directories should be added")
+			Expect(tree.HasFile("frontend")).Should(BeFalse(), "directories should not be files")
+			Expect(tree.HasFile("frontend/src/index.js")).Should(BeTrue(), "nested files should be present")
+		})
+
+		It("ReadDir", func() {
+			entries, err := tree.ReadDir(ctx, ".")
+			Expect(err).Should(BeNil())
+			Expect(entries).Should(Equal([]Entry{{Path: "docs", Dir: true}, {Path: "frontend", Dir: true}, {Path: "package.json"}, {Path: "pom.xml"}}), "root entries should be listed")
+
+			_, err = tree.ReadDir(ctx, "pom.xml")
+			Expect(err).Should(Equal(ErrFileNotFound), "files should not be listed")
+		})
+
+		It("ReadFile", func() {
+			content, err := tree.ReadFile(ctx, "frontend/src/index.js")
+			Expect(err).Should(BeNil())
+			Expect(string(content)).Should(Equal("frontend/src/index.js"))
+
+			_, err = tree.ReadFile(ctx, "build.gradle")
+			Expect(err).Should(Equal(ErrFileNotFound), "missing files should not be fetched")
+			Expect(fetched).Should(Equal([]string{"frontend/src/index.js"}), "only index.js should be fetched")
+		})
+
+		It("Detect", func() {
+			detection, err := Detect(ctx, tree, nil)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(BeEquivalentTo("maven"), "build tool type should be 'maven'")
+			Expect(detection.Matches).Should(HaveLen(2), "maven and nodejs should match")
+			Expect(fetched).Should(Equal([]string{"pom.xml", "package.json"}), "only the manifests should be fetched to describe the builds")
+		})
+
+		It("Sub", func() {
+			sub, err := tree.Sub("frontend/")
+			Expect(err).Should(BeNil())
+			Expect(sub.Dir()).Should(Equal("frontend"), "tree should be rooted at 'frontend'")
+			Expect(sub.Paths()).Should(Equal([]string{"src", "src/index.js"}), "paths should be relative to 'frontend'")
+
+			content, err := sub.ReadFile(ctx, "src/index.js")
+			Expect(err).Should(BeNil())
+			Expect(string(content)).Should(Equal("frontend/src/index.js"), "files should be fetched from 'frontend'")
+
+			sub, err = sub.Sub("src/index.js")
+			Expect(err).Should(BeNil())
+			Expect(sub.Dir()).Should(Equal("frontend/src"), "files should resolve to their directory")
+
+			_, err = tree.Sub("backend")
+			Expect(err).Should(Equal(ErrResourceNotFound), "missing directories should not be found")
+		})
+
+		It("Detect - subdirectory", func() {
+			sub, err := tree.Sub("frontend")
+			Expect(err).Should(BeNil())
+			detection, err := Detect(ctx, sub, nil)
+			Expect(err).Should(BeNil())
+			Expect(detection.BuildType).Should(BeEquivalentTo("unknown"), "marker files of the root should not match")
+			Expect(detection.Path).Should(Equal("frontend"), "path should be 'frontend'")
+		})
+	})
+})
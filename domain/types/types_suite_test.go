@@ -1,13 +1,54 @@
 package types_test
 
 import (
+	"context"
+	"path"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/fabric8-services/build-tool-detector/domain/types"
 )
 
 func TestBuildtype(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Types Suite")
 }
+
+// mapSource is a Source serving
+// files held in memory.
+type mapSource map[string]string
+
+func (m mapSource) ReadFile(ctx context.Context, file string) ([]byte, error) {
+	content, ok := m[file]
+	if !ok {
+		return nil, types.ErrFileNotFound
+	}
+	return []byte(content), nil
+}
+
+func (m mapSource) ReadDir(ctx context.Context, dir string) ([]types.Entry, error) {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	seen := make(map[string]bool)
+	var entries []types.Entry
+	for file := range m {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+		name := strings.SplitN(strings.TrimPrefix(file, prefix), "/", 2)
+		entry := types.Entry{Path: path.Join(dir, name[0]), Dir: len(name) > 1}
+		if !seen[entry.Path] {
+			seen[entry.Path] = true
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, types.ErrFileNotFound
+	}
+	return entries, nil
+}
@@ -0,0 +1,268 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	lernaJSON         = "lerna.json"
+	nxJSON            = "nx.json"
+	turboJSON         = "turbo.json"
+	pnpmWorkspaceYAML = "pnpm-workspace.yaml"
+
+	// Lerna workspaces orchestrated by lerna.
+	Lerna = "lerna"
+
+	// Nx workspaces orchestrated by nx.
+	Nx = "nx"
+
+	// Turborepo workspaces orchestrated by turborepo.
+	Turborepo = "turborepo"
+
+	globWildcard  = "**"
+	globMeta      = "*?["
+	globNegation  = "!"
+	nxDefaultApps = "apps"
+	nxDefaultLibs = "libs"
+	lernaDefault  = "packages/*"
+	globChildren  = "/*"
+)
+
+// NodeWorkspaces holds the workspaces of a javascript
+// monorepo. Tool orchestrates the workspaces, either a
+// monorepo tool or the package manager. Packages are
+// the directories of the workspace packages, sorted.
+type NodeWorkspaces struct {
+	Tool     string
+	Packages []string
+}
+
+// lernaConfig is the subset of
+// lerna.json used for detection.
+type lernaConfig struct {
+	Packages []string `json:"packages"`
+}
+
+// nxConfig is the subset of
+// nx.json used for detection.
+type nxConfig struct {
+	WorkspaceLayout struct {
+		AppsDir string `json:"appsDir"`
+		LibsDir string `json:"libsDir"`
+	} `json:"workspaceLayout"`
+}
+
+// pnpmWorkspace is the subset of
+// pnpm-workspace.yaml used for detection.
+type pnpmWorkspace struct {
+	Packages []string `yaml:"packages"`
+}
+
+// workspacesOf returns the globs of the workspaces field
+// of package.json, either a list or, with yarn, an
+// object holding the list as packages.
+func workspacesOf(workspaces json.RawMessage) []string {
+	var globs []string
+	if json.Unmarshal(workspaces, &globs) == nil {
+		return globs
+	}
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	json.Unmarshal(workspaces, &object)
+	return object.Packages
+}
+
+// describeWorkspaces reports the tool orchestrating the
+// workspaces and the packages matching their globs. The
+// packages listed by lerna.json come first, then those of
+// pnpm-workspace.yaml and of package.json, before the
+// default layouts of lerna and nx. Nothing is reported
+// without workspaces.
+func describeWorkspaces(ctx context.Context, src Source, files []string, manifest packageManifest, build *NodeJSBuild) error {
+	var lerna lernaConfig
+	if err := readJSON(ctx, src, files, lernaJSON, &lerna); err != nil {
+		return err
+	}
+	var nx nxConfig
+	if err := readJSON(ctx, src, files, nxJSON, &nx); err != nil {
+		return err
+	}
+	var pnpm pnpmWorkspace
+	if contains(files, pnpmWorkspaceYAML) {
+		content, err := src.ReadFile(ctx, pnpmWorkspaceYAML)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(content, &pnpm); err != nil {
+			return err
+		}
+	}
+
+	globs := lerna.Packages
+	for _, candidates := range [][]string{pnpm.Packages, workspacesOf(manifest.Workspaces)} {
+		if len(globs) == 0 {
+			globs = candidates
+		}
+	}
+	if len(globs) == 0 && contains(files, lernaJSON) {
+		globs = []string{lernaDefault}
+	}
+	if len(globs) == 0 && contains(files, nxJSON) {
+		globs = []string{
+			defaultOf(nx.WorkspaceLayout.AppsDir, nxDefaultApps) + globChildren,
+			defaultOf(nx.WorkspaceLayout.LibsDir, nxDefaultLibs) + globChildren,
+		}
+	}
+	if len(globs) == 0 {
+		return nil
+	}
+
+	workspaces := NodeWorkspaces{Tool: build.PackageManager}
+	switch {
+	case contains(files, turboJSON):
+		workspaces.Tool = Turborepo
+	case contains(files, nxJSON):
+		workspaces.Tool = Nx
+	case contains(files, lernaJSON):
+		workspaces.Tool = Lerna
+	case contains(files, pnpmWorkspaceYAML):
+		workspaces.Tool = PNPM
+	}
+	packages, err := expandGlobs(ctx, src, globs)
+	if err != nil {
+		return err
+	}
+	workspaces.Packages = packages
+	build.Workspaces = &workspaces
+	return nil
+}
+
+// readJSON decodes the file into value
+// if it is part of the marker files.
+func readJSON(ctx context.Context, src Source, files []string, file string, value interface{}) error {
+	if !contains(files, file) {
+		return nil
+	}
+	content, err := src.ReadFile(ctx, file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, value)
+}
+
+// defaultOf returns the value,
+// or fallback if it is empty.
+func defaultOf(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// expandGlobs returns the directories matching the globs
+// which contain a package.json file, sorted. Globs are
+// matched a directory at a time, ** matching any number
+// of directories. Directories matching a negated glob
+// and dependency directories are left out.
+func expandGlobs(ctx context.Context, src Source, globs []string) ([]string, error) {
+	var excluded []string
+	found := make(map[string]bool)
+	for _, glob := range globs {
+		if strings.HasPrefix(glob, globNegation) {
+			excluded = append(excluded, cleanGlob(strings.TrimPrefix(glob, globNegation)))
+			continue
+		}
+		dirs, err := matchGlob(ctx, src, root, strings.Split(cleanGlob(glob), "/"))
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			found[dir] = true
+		}
+	}
+
+	var packages []string
+	for dir := range found {
+		if !isExcluded(dir, excluded) {
+			packages = append(packages, dir)
+		}
+	}
+	sort.Strings(packages)
+	return packages, nil
+}
+
+// matchGlob returns the package directories under dir
+// matching the segments of a glob.
+func matchGlob(ctx context.Context, src Source, dir string, segments []string) ([]string, error) {
+	entries, err := src.ReadDir(ctx, dir)
+	if err == ErrFileNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		for _, entry := range entries {
+			if !entry.Dir && path.Base(entry.Path) == packageJSON {
+				return []string{dir}, nil
+			}
+		}
+		return nil, nil
+	}
+
+	segment := segments[0]
+	if !strings.ContainsAny(segment, globMeta) {
+		return matchGlob(ctx, src, path.Join(dir, segment), segments[1:])
+	}
+
+	var matches []string
+	if segment == globWildcard {
+		matches, err = matchGlob(ctx, src, dir, segments[1:])
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range entries {
+		name := path.Base(entry.Path)
+		if !entry.Dir || ignoredDirs[name] || strings.HasPrefix(name, ".") {
+			continue
+		}
+		rest := segments
+		if segment != globWildcard {
+			if ok, _ := path.Match(segment, name); !ok {
+				continue
+			}
+			rest = segments[1:]
+		}
+		dirs, err := matchGlob(ctx, src, entry.Path, rest)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, dirs...)
+	}
+	return matches, nil
+}
+
+// cleanGlob removes the leading ./
+// and the trailing slash of a glob.
+func cleanGlob(glob string) string {
+	return strings.Trim(path.Clean(strings.TrimSpace(glob)), "/")
+}
+
+// isExcluded returns whether the directory
+// matches one of the negated globs.
+func isExcluded(dir string, excluded []string) bool {
+	for _, glob := range excluded {
+		if ok, _ := path.Match(glob, dir); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"net/http"
+	"os"
 
 	"github.com/fabric8-services/build-tool-detector/app"
 	"github.com/fabric8-services/build-tool-detector/config"
 	"github.com/fabric8-services/build-tool-detector/controllers"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
 	"github.com/fabric8-services/build-tool-detector/log"
 	"github.com/fabric8-services/fabric8-common/goamiddleware"
 	"github.com/fabric8-services/fabric8-common/token"
@@ -26,6 +28,14 @@ func main() {
 	// Get a new configuration.
 	configuration := config.New()
 
+	// Load the detection rules, invalid rules prevent the startup.
+	if rulesFile := configuration.GetDetectorRulesFile(); rulesFile != "" {
+		if err := types.LoadRulesFile(rulesFile); err != nil {
+			log.Logger().WithError(err).WithField("file", rulesFile).Error("failed to load the detection rules")
+			os.Exit(1)
+		}
+	}
+
 	service := goa.New(buildToolDetector)
 
 	// Mount middleware.
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fabric8-services/build-tool-detector/config"
+	"github.com/fabric8-services/build-tool-detector/domain/render"
+	"github.com/fabric8-services/build-tool-detector/domain/repository/local"
+	"github.com/fabric8-services/build-tool-detector/domain/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	currentDir   = "."
+	detailedView = "detailed"
+)
+
+// newDetectLocalCommand creates the command detecting the
+// build tool of a local directory, such as a checked out
+// workspace, without calling the service.
+func newDetectLocalCommand() *cobra.Command {
+	var path, view string
+	var explain bool
+	command := &cobra.Command{
+		Use:   "detect-local [directory]",
+		Short: "Detect the build tool of a local directory, the current directory by default",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := currentDir
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return detectLocal(os.Stdout, dir, path, view, explain)
+		},
+	}
+	command.Flags().StringVar(&path, "path", "", "Directory of the repository the build tools are detected in")
+	command.Flags().StringVar(&view, "view", "default", "Response view, detailed lists every detected build tool")
+	command.Flags().BoolVar(&explain, "explain", false, "Explain how the build tool was detected, in the detailed view")
+	return command
+}
+
+// detectLocal detects the build tool of the directory
+// and writes the response the service would return.
+// The detection rules and precedence are configured
+// as for the service.
+func detectLocal(out io.Writer, dir string, path string, view string, explain bool) error {
+	configuration := config.New()
+	if rulesFile := configuration.GetDetectorRulesFile(); rulesFile != "" {
+		if err := types.LoadRulesFile(rulesFile); err != nil {
+			return fmt.Errorf("%s: %v", rulesFile, err)
+		}
+	}
+
+	ctx := context.Background()
+	var explanation *types.Explanation
+	if explain {
+		ctx, explanation = types.WithExplanation(ctx)
+	}
+	repositoryService, err := local.Create(dir, path, *configuration)
+	if err != nil {
+		return err
+	}
+	// An unknown build tool is still reported.
+	detection, err := repositoryService.DetectBuildTool(ctx)
+	if err != nil && err != types.ErrFailedContentRetrieval {
+		return err
+	}
+
+	var response interface{} = render.NewBuildTool(detection)
+	if view == detailedView {
+		detailed := render.NewDetailed(detection)
+		detailed.Explanation = render.NewExplanation(explanation)
+		response = detailed
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(response)
+}
@@ -45,6 +45,9 @@ func main() {
 	// Register API commands
 	cli.RegisterCommands(app, c)
 
+	// Register offline commands
+	app.AddCommand(newDetectLocalCommand())
+
 	// Execute!
 	if err := app.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, err.Error())